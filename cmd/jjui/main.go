@@ -13,13 +13,17 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/muesli/termenv"
 
 	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/ui/context"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/idursun/jjui/internal/ui"
+	"github.com/idursun/jjui/internal/ui/remote"
+	"github.com/idursun/jjui/internal/ui/revisions"
 )
 
 var Version string
@@ -43,6 +47,15 @@ var (
 	version    bool
 	editConfig bool
 	help       bool
+	socketPath bool
+	pick       string
+	pickFormat string
+	printView  bool
+	printWidth int
+	printColor string
+	record     string
+	replay     string
+	checkKeys  bool
 )
 
 func init() {
@@ -55,6 +68,15 @@ func init() {
 	flag.BoolVar(&version, "version", false, "Show version information")
 	flag.BoolVar(&editConfig, "config", false, "Open configuration file in $EDITOR")
 	flag.BoolVar(&help, "help", false, "Show help information")
+	flag.StringVar(&pick, "pick", "", "Choose revisions or files (revisions|files) and print them to stdout")
+	flag.StringVar(&pickFormat, "pick-format", "", "Format of the printed lines with $change_id, $commit_id and $file placeholders (default: $change_id for revisions, $file for files)")
+	flag.BoolVar(&printView, "print", false, "Print the revisions to stdout without starting the UI")
+	flag.IntVar(&printWidth, "print-width", 0, "Width of the printed revisions (default: width of the terminal, or 80)")
+	flag.StringVar(&printColor, "print-color", "auto", "Print the revisions with colors (auto|always|never)")
+	flag.StringVar(&record, "record", os.Getenv(context.RecordEnv), "Record the jj commands and their output to a file (or set $"+context.RecordEnv+")")
+	flag.StringVar(&replay, "replay", "", "Replay the jj commands from a recording instead of running them")
+	flag.BoolVar(&socketPath, "socket-path", false, "Print the path of the remote control socket of the repo")
+	flag.BoolVar(&checkKeys, "check-keys", false, "Report the keys bound to more than one action and exit")
 
 	flag.Usage = func() {
 		fmt.Printf("Usage: jjui [flags] [location]\n")
@@ -85,6 +107,19 @@ func main() {
 	case editConfig:
 		exitCode := config.Edit()
 		os.Exit(exitCode)
+	case checkKeys:
+		os.Exit(runCheckKeys())
+	}
+
+	// the picker is drawn on the terminal so that stdout only has the picked items and can be used in pipelines
+	var pickerOutput io.Writer = os.Stderr
+	if pick != "" {
+		if tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0); err == nil {
+			defer tty.Close()
+			pickerOutput = tty
+		}
+		// styles are rendered with the color profile of the terminal rather than the one of stdout
+		lipgloss.SetDefaultRenderer(lipgloss.NewRenderer(pickerOutput))
 	}
 
 	var location string
@@ -101,10 +136,14 @@ func main() {
 		}
 	}
 
-	rootLocation, err := getJJRootDir(location)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: There is no jj repo in \"%s\".\n", location)
-		os.Exit(1)
+	// a recording is replayed without the repository it was recorded in
+	rootLocation := location
+	var err error
+	if replay == "" {
+		if rootLocation, err = getJJRootDir(location); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: There is no jj repo in \"%s\".\n", location)
+			os.Exit(1)
+		}
 	}
 
 	if len(os.Getenv("DEBUG")) > 0 {
@@ -122,7 +161,25 @@ func main() {
 		config.Current.Limit = limit
 	}
 
-	appContext := context.NewAppContext(rootLocation)
+	var options []context.Option
+	if replay != "" {
+		runner, err := loadReplay(replay)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading recording: %v\n", err)
+			os.Exit(1)
+		}
+		options = append(options, context.WithCommandRunner(runner))
+	} else if record != "" {
+		f, err := os.Create(record)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating recording: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		options = append(options, context.WithRecorder(context.NewRecorder(f)))
+	}
+
+	appContext := context.NewAppContext(rootLocation, options...)
 	defer appContext.Histories.Flush()
 	if output, err := config.LoadConfigFile(); err == nil {
 		if err := config.Current.Load(string(output)); err != nil {
@@ -198,9 +255,140 @@ func main() {
 	}
 	appContext.CurrentRevset = appContext.DefaultRevset
 
-	p := tea.NewProgram(ui.New(appContext), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	path := config.Current.Remote.Socket
+	if path == "" {
+		path = remote.SocketPath(rootLocation)
+	}
+	if socketPath {
+		fmt.Println(path)
+		os.Exit(0)
+	}
+
+	if printView {
+		os.Exit(runPrint(appContext))
+	}
+
+	if pick != "" {
+		code := runPicker(appContext, ui.PickMode(pick), pickerOutput)
+		appContext.Histories.Flush()
+		os.Exit(code)
+	}
+
+	model := ui.New(appContext)
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	if conflicts := keyConflicts(appContext.CustomCommands); len(conflicts) > 0 {
+		lines := []string{"Some keys are bound to more than one action (see jjui --check-keys):"}
+		for _, conflict := range conflicts {
+			lines = append(lines, conflict.String())
+		}
+		go p.Send(common.FlashMsg{Text: strings.Join(lines, "\n"), Error: true})
+	}
+	if config.Current.Remote.Enabled {
+		if server, err := remote.Listen(appContext, path, p.Send); err != nil {
+			log.Println("remote control is disabled:", err)
+		} else {
+			defer server.Close()
+			os.Setenv(remote.SocketEnv, path)
+			go server.Serve()
+		}
+	}
+	_, err = p.Run()
+	model.(ui.Model).Close()
+	if err != nil {
 		fmt.Printf("Error running program: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// runPicker runs the UI as a chooser and prints the picked items to stdout
+func runPicker(appContext *context.MainContext, mode ui.PickMode, output io.Writer) int {
+	if mode != ui.PickRevisions && mode != ui.PickFiles {
+		fmt.Fprintf(os.Stderr, "Error: --pick must be either %s or %s\n", ui.PickRevisions, ui.PickFiles)
+		return 2
+	}
+	picker := ui.NewPicker(appContext, mode, pickFormat)
+	if _, err := tea.NewProgram(picker, tea.WithAltScreen(), tea.WithInputTTY(), tea.WithOutput(output)).Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
+		return 1
+	}
+	if len(picker.Picked()) == 0 {
+		return 1
+	}
+	for _, line := range picker.Picked() {
+		fmt.Println(line)
+	}
+	return 0
+}
+
+// runPrint renders the revisions of the current revset to stdout
+func runPrint(appContext *context.MainContext) int {
+	isTerminal := term.IsTerminal(os.Stdout.Fd())
+	var plain bool
+	switch printColor {
+	case "auto":
+		plain = !isTerminal
+	case "always":
+		lipgloss.SetColorProfile(termenv.TrueColor)
+	case "never":
+		plain = true
+	default:
+		fmt.Fprintf(os.Stderr, "Error: --print-color must be one of auto, always or never\n")
+		return 2
+	}
+	width := printWidth
+	if width <= 0 {
+		width = 80
+		if w, _, err := term.GetSize(os.Stdout.Fd()); err == nil && isTerminal {
+			width = w
+		}
+	}
+	if err := revisions.Print(appContext, os.Stdout, appContext.CurrentRevset, width, plain); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runCheckKeys prints the key conflicts of the configuration, it fails when there are any
+func runCheckKeys() int {
+	output, err := config.LoadConfigFile()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := config.Current.Load(string(output)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+		return 1
+	}
+	customCommands, err := context.LoadCustomCommands(string(output))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading custom commands: %v\n", err)
+		return 1
+	}
+	conflicts := keyConflicts(customCommands)
+	for _, conflict := range conflicts {
+		fmt.Println(conflict)
+	}
+	if len(conflicts) > 0 {
+		return 1
+	}
+	fmt.Println("No key conflicts found")
+	return 0
+}
+
+func keyConflicts(customCommands map[string]context.CustomCommand) []config.KeyConflict {
+	commandKeys := make(map[string][]string)
+	for name, command := range customCommands {
+		commandKeys[name] = command.Binding().Keys()
+	}
+	return config.FindKeyConflicts(config.Current.Keys, commandKeys)
+}
+
+func loadReplay(path string) (*context.ReplayCommandRunner, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return context.NewReplayCommandRunner(f)
+}
@@ -9,7 +9,7 @@ import (
 	"path"
 )
 
-//go:embed default/*.toml
+//go:embed default/*.toml default/presets/*.toml
 var configFS embed.FS
 
 var Current = loadDefaultConfig()
@@ -20,6 +20,13 @@ type Config struct {
 	Preview                        PreviewConfig     `toml:"preview"`
 	OpLog                          OpLogConfig       `toml:"oplog"`
 	Graph                          GraphConfig       `toml:"graph"`
+	Run                            RunConfig         `toml:"run"`
+	Bookmark                       BookmarkConfig    `toml:"bookmark"`
+	Submit                         SubmitConfig      `toml:"submit"`
+	Status                         StatusConfig      `toml:"status"`
+	Watch                          WatchConfig       `toml:"watch"`
+	Remote                         RemoteConfig      `toml:"remote"`
+	KeySequence                    KeySequenceConfig `toml:"key_sequence"`
 	ExperimentalLogBatchingEnabled bool              `toml:"experimental_log_batching_enabled"`
 	Limit                          int               `toml:"limit"`
 }
@@ -133,6 +140,56 @@ type GraphConfig struct {
 	BatchSize int `toml:"batch_size"`
 }
 
+type RunConfig struct {
+	// shell command executed for each revision, `$change_id` is replaced with the revision being checked
+	Command string `toml:"command"`
+	// revisions to run against when nothing is checked, `$change_id` is replaced with the selected revision
+	Revset string `toml:"revset"`
+}
+
+type BookmarkConfig struct {
+	// name of the bookmarks created for each revision of a stack, supports `{user}`, `{description_slug}` and `{change_id}`
+	NameTemplate string `toml:"name_template"`
+}
+
+type SubmitConfig struct {
+	// shell commands creating and updating the code review of a bookmark, the last line of their output is
+	// recorded as the review id. `$bookmark`, `$base`, `$title`, `$description` and `$review` are replaced
+	// with shell quoted values
+	Create string `toml:"create"`
+	Update string `toml:"update"`
+}
+
+type StatusConfig struct {
+	// shell command receiving the ids of the pushed commits on stdin, one per line, and printing their
+	// statuses as a JSON array of `{"commit_id": "...", "state": "success|failure|pending", "label": "..."}`
+	Command string `toml:"command"`
+}
+
+type WatchConfig struct {
+	// watches the repository and refreshes the revisions when it changes, polling every
+	// `auto_refresh_interval` seconds is the fallback when it can't be watched
+	Enabled bool `toml:"enabled"`
+	// milliseconds to wait for the changes to settle before refreshing
+	Debounce int `toml:"debounce"`
+	// glob patterns of the working copy paths whose changes are ignored, `.jj` and `.git` are always ignored
+	Ignore []string `toml:"ignore"`
+}
+
+type KeySequenceConfig struct {
+	// milliseconds to wait for the next key of a key sequence such as "g g"
+	Timeout int `toml:"timeout"`
+	// digits typed before a movement or selection key repeat it, e.g. `5j`
+	Count bool `toml:"count"`
+}
+
+type RemoteConfig struct {
+	// listens on a unix socket for JSON commands sent by editors and scripts
+	Enabled bool `toml:"enabled"`
+	// path of the socket, a path in the runtime directory is derived from the repository when empty
+	Socket string `toml:"socket"`
+}
+
 type ShowOption string
 
 const (
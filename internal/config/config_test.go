@@ -75,3 +75,95 @@ complex = { fg = "blue", bg = "white", bold = true }
 	assert.Equal(t, "white", config.UI.Colors["complex"].Bg)
 	assert.True(t, config.UI.Colors["complex"].Bold)
 }
+
+func TestKeySequences(t *testing.T) {
+	content := `
+[keys]
+up = ["up", "k"]
+jump_to_working_copy = ["@", "g g"]
+
+[keys.preview]
+mode = ["p", "space p"]
+`
+	config := &Config{}
+	err := config.Load(content)
+	assert.NoError(t, err)
+
+	sequences := KeySequences(config.GetKeyMap())
+	assert.Contains(t, sequences, KeySequence{Key: "g g", Keys: []string{"g", "g"}, Help: "jump to working copy"})
+	assert.Contains(t, sequences, KeySequence{Key: "space p", Keys: []string{" ", "p"}, Help: "preview"})
+	assert.Nil(t, SplitKeySequence("ctrl+r"))
+}
+
+func TestLoad_KeyPreset(t *testing.T) {
+	content := `
+[keys]
+preset = "emacs"
+up = ["k"]
+`
+	config := loadDefaultConfig()
+	err := config.Load(content)
+	assert.NoError(t, err)
+	assert.Equal(t, keys{"k"}, config.Keys.Up)
+	assert.Equal(t, keys{"down", "ctrl+n"}, config.Keys.Down)
+	assert.Equal(t, keys{"alt+up"}, config.Keys.Preview.ScrollUp)
+	assert.Equal(t, keys{"n"}, config.Keys.New)
+}
+
+func TestLoad_KeyPresetMovements(t *testing.T) {
+	vim := loadDefaultConfig()
+	assert.NoError(t, vim.Load(`keys.preset = "vim"`))
+	assert.Equal(t, keys{"home", "g g"}, vim.Keys.JumpToTop)
+	assert.Equal(t, keys{"end", "G"}, vim.Keys.JumpToBottom)
+	assert.Equal(t, keys{"pgdown", "ctrl+d"}, vim.Keys.HalfPageDown)
+	assert.Equal(t, keys{"g"}, vim.Keys.Git.Mode)
+
+	helix := loadDefaultConfig()
+	assert.NoError(t, helix.Load(`keys.preset = "helix"`))
+	assert.Equal(t, keys{"end", "g e"}, helix.Keys.JumpToBottom)
+	assert.Equal(t, keys{"G"}, helix.Keys.Git.Mode)
+}
+
+func TestLoad_UnknownKeyPreset(t *testing.T) {
+	config := loadDefaultConfig()
+	err := config.Load(`keys.preset = "nano"`)
+	assert.ErrorContains(t, err, `unknown keys.preset "nano"`)
+}
+
+func TestFindKeyConflicts_Presets(t *testing.T) {
+	assert.Empty(t, FindKeyConflicts(loadDefaultConfig().Keys, nil))
+	for _, preset := range KeyPresets() {
+		config := loadDefaultConfig()
+		assert.NoError(t, config.Load(`keys.preset = "`+preset+`"`))
+		assert.Empty(t, FindKeyConflicts(config.Keys, nil), preset)
+	}
+}
+
+func TestFindKeyConflicts_NavigationInModes(t *testing.T) {
+	content := `
+[keys]
+divergence.next = ["tab", "j"]
+`
+	config := loadDefaultConfig()
+	assert.NoError(t, config.Load(content))
+	assert.Equal(t, []KeyConflict{
+		{Key: "j", Actions: []string{"divergence.next", "down"}},
+	}, FindKeyConflicts(config.Keys, nil))
+}
+
+func TestFindKeyConflicts(t *testing.T) {
+	content := `
+[keys]
+preview.mode = ["n"]
+details.select = ["s"]
+`
+	config := loadDefaultConfig()
+	assert.NoError(t, config.Load(content))
+	conflicts := FindKeyConflicts(config.Keys, map[string][]string{"show diff": {"d"}, "log": {"w"}})
+	assert.Equal(t, []KeyConflict{
+		{Key: "d", Actions: []string{"custom_commands.show diff", "diff"}},
+		{Key: "s", Actions: []string{"details.select", "details.split"}},
+		{Key: "n", Actions: []string{"new", "preview.mode"}},
+	}, conflicts)
+	assert.Equal(t, "s is bound to details.select, details.split", conflicts[1].String())
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// KeyConflict is a key bound to more than one action of the same mode
+type KeyConflict struct {
+	Key string
+	// Actions are named as they are configured, e.g. `details.select` or `custom_commands.name`
+	Actions []string
+}
+
+func (c KeyConflict) String() string {
+	return fmt.Sprintf("%s is bound to %s", JoinKeys([]string{c.Key}), strings.Join(c.Actions, ", "))
+}
+
+// navigated are the modes which match up and down before their own keys
+var navigated = []string{"details", "evolog", "oplog", "run", "divergence", "bookmark_browser", "bookmark_cleanup", "command_log"}
+
+// FindKeyConflicts returns the keys bound to more than one action of the same mode, and the keys of
+// the custom commands which shadow the keys of the revisions. The keys of `[keys]`, the keys which
+// enter a mode and the keys of the preview are used in the revisions; the other keys of a mode which
+// is entered by a key are used together with apply and cancel, and with up and down in the modes
+// which move in a list.
+func FindKeyConflicts(m KeyMappings[keys], customCommands map[string][]string) []KeyConflict {
+	const revisions = ""
+	bindings := make(map[string]map[string][]string)
+	bind := func(mode string, action string, keys []string) {
+		if bindings[mode] == nil {
+			bindings[mode] = make(map[string][]string)
+		}
+		for _, k := range keys {
+			if k == "space" {
+				k = " "
+			}
+			if !slices.Contains(bindings[mode][k], action) {
+				bindings[mode][k] = append(bindings[mode][k], action)
+			}
+		}
+	}
+
+	v := reflect.ValueOf(m)
+	for i := range v.NumField() {
+		field, name := v.Field(i), v.Type().Field(i).Tag.Get("toml")
+		if k, ok := field.Interface().(keys); ok {
+			if name != "apply" && name != "cancel" {
+				bind(revisions, name, k)
+			}
+			continue
+		}
+		if field.Kind() != reflect.Struct {
+			continue
+		}
+		mode := name
+		if _, ok := field.Type().FieldByName("Mode"); ok || slices.Contains(navigated, mode) {
+			bind(mode, "apply", m.Apply)
+			bind(mode, "cancel", m.Cancel)
+		}
+		if slices.Contains(navigated, mode) {
+			bind(mode, "up", m.Up)
+			bind(mode, "down", m.Down)
+		}
+		for j := range field.NumField() {
+			action := field.Type().Field(j).Tag.Get("toml")
+			k := field.Field(j).Interface().(keys)
+			if action == "mode" || mode == "preview" {
+				bind(revisions, mode+"."+action, k)
+				continue
+			}
+			bind(mode, mode+"."+action, k)
+		}
+	}
+	for name, k := range customCommands {
+		bind(revisions, "custom_commands."+name, k)
+	}
+
+	var conflicts []KeyConflict
+	for _, mode := range bindings {
+		for k, actions := range mode {
+			if len(actions) > 1 {
+				sort.Strings(actions)
+				conflicts = append(conflicts, KeyConflict{Key: k, Actions: actions})
+			}
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return strings.Join(conflicts[i].Actions, " ") < strings.Join(conflicts[j].Actions, " ")
+	})
+	return conflicts
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"reflect"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -13,6 +14,10 @@ func Convert(m KeyMappings[keys]) KeyMappings[key.Binding] {
 		JumpToParent:      key.NewBinding(key.WithKeys(m.JumpToParent...), key.WithHelp(JoinKeys(m.JumpToParent), "jump to parent")),
 		JumpToChildren:    key.NewBinding(key.WithKeys(m.JumpToChildren...), key.WithHelp(JoinKeys(m.JumpToChildren), "jump to children")),
 		JumpToWorkingCopy: key.NewBinding(key.WithKeys(m.JumpToWorkingCopy...), key.WithHelp(JoinKeys(m.JumpToWorkingCopy), "jump to working copy")),
+		JumpToTop:         key.NewBinding(key.WithKeys(m.JumpToTop...), key.WithHelp(JoinKeys(m.JumpToTop), "jump to top")),
+		JumpToBottom:      key.NewBinding(key.WithKeys(m.JumpToBottom...), key.WithHelp(JoinKeys(m.JumpToBottom), "jump to bottom")),
+		HalfPageDown:      key.NewBinding(key.WithKeys(m.HalfPageDown...), key.WithHelp(JoinKeys(m.HalfPageDown), "half page down")),
+		HalfPageUp:        key.NewBinding(key.WithKeys(m.HalfPageUp...), key.WithHelp(JoinKeys(m.HalfPageUp), "half page up")),
 		Apply:             key.NewBinding(key.WithKeys(m.Apply...), key.WithHelp(JoinKeys(m.Apply), "apply")),
 		Cancel:            key.NewBinding(key.WithKeys(m.Cancel...), key.WithHelp(JoinKeys(m.Cancel), "cancel")),
 		ToggleSelect:      key.NewBinding(key.WithKeys(m.ToggleSelect...), key.WithHelp(JoinKeys(m.ToggleSelect), "toggle selection")),
@@ -39,8 +44,12 @@ func Convert(m KeyMappings[keys]) KeyMappings[key.Binding] {
 		QuickSearch:      key.NewBinding(key.WithKeys(m.QuickSearch...), key.WithHelp(JoinKeys(m.QuickSearch), "quick search")),
 		QuickSearchCycle: key.NewBinding(key.WithKeys(m.QuickSearchCycle...), key.WithHelp(JoinKeys(m.QuickSearchCycle), "locate next match")),
 		CustomCommands:   key.NewBinding(key.WithKeys(m.CustomCommands...), key.WithHelp(JoinKeys(m.CustomCommands), "custom commands menu")),
+		CommandPalette:   key.NewBinding(key.WithKeys(m.CommandPalette...), key.WithHelp(JoinKeys(m.CommandPalette), "command palette")),
+		MacroRecord:      key.NewBinding(key.WithKeys(m.MacroRecord...), key.WithHelp(JoinKeys(m.MacroRecord), "record macro")),
+		MacroReplay:      key.NewBinding(key.WithKeys(m.MacroReplay...), key.WithHelp(JoinKeys(m.MacroReplay), "replay macro")),
 		Leader:           key.NewBinding(key.WithKeys(m.Leader...), key.WithHelp(JoinKeys(m.Leader), "leader")),
 		Suspend:          key.NewBinding(key.WithKeys(m.Suspend...), key.WithHelp(JoinKeys(m.Suspend), "suspend")),
+		CancelCommand:    key.NewBinding(key.WithKeys(m.CancelCommand...), key.WithHelp(JoinKeys(m.CancelCommand), "cancel running command")),
 		ExecJJ:           key.NewBinding(key.WithKeys(m.ExecJJ...), key.WithHelp(JoinKeys(m.ExecJJ), "interactive jj")),
 		ExecShell:        key.NewBinding(key.WithKeys(m.ExecShell...), key.WithHelp(JoinKeys(m.ExecShell), "interactive shell command")),
 		Rebase: rebaseModeKeys[key.Binding]{
@@ -83,6 +92,20 @@ func Convert(m KeyMappings[keys]) KeyMappings[key.Binding] {
 			Forget:  key.NewBinding(key.WithKeys(m.Bookmark.Forget...), key.WithHelp(JoinKeys(m.Bookmark.Forget), "forget")),
 			Track:   key.NewBinding(key.WithKeys(m.Bookmark.Track...), key.WithHelp(JoinKeys(m.Bookmark.Track), "track")),
 			Untrack: key.NewBinding(key.WithKeys(m.Bookmark.Untrack...), key.WithHelp(JoinKeys(m.Bookmark.Untrack), "untrack")),
+			Cleanup: key.NewBinding(key.WithKeys(m.Bookmark.Cleanup...), key.WithHelp(JoinKeys(m.Bookmark.Cleanup), "cleanup")),
+			Browse:  key.NewBinding(key.WithKeys(m.Bookmark.Browse...), key.WithHelp(JoinKeys(m.Bookmark.Browse), "browse")),
+		},
+		BookmarkCleanup: bookmarkCleanupModeKeys[key.Binding]{
+			ToggleAll: key.NewBinding(key.WithKeys(m.BookmarkCleanup.ToggleAll...), key.WithHelp(JoinKeys(m.BookmarkCleanup.ToggleAll), "toggle all")),
+			Delete:    key.NewBinding(key.WithKeys(m.BookmarkCleanup.Delete...), key.WithHelp(JoinKeys(m.BookmarkCleanup.Delete), "delete checked")),
+			Forget:    key.NewBinding(key.WithKeys(m.BookmarkCleanup.Forget...), key.WithHelp(JoinKeys(m.BookmarkCleanup.Forget), "forget checked")),
+			Push:      key.NewBinding(key.WithKeys(m.BookmarkCleanup.Push...), key.WithHelp(JoinKeys(m.BookmarkCleanup.Push), "push deletion")),
+		},
+		BookmarkBrowser: bookmarkBrowserModeKeys[key.Binding]{
+			Mode:   key.NewBinding(key.WithKeys(m.BookmarkBrowser.Mode...), key.WithHelp(JoinKeys(m.BookmarkBrowser.Mode), "bookmark browser")),
+			Sort:   key.NewBinding(key.WithKeys(m.BookmarkBrowser.Sort...), key.WithHelp(JoinKeys(m.BookmarkBrowser.Sort), "sort")),
+			Filter: key.NewBinding(key.WithKeys(m.BookmarkBrowser.Filter...), key.WithHelp(JoinKeys(m.BookmarkBrowser.Filter), "filter")),
+			Revset: key.NewBinding(key.WithKeys(m.BookmarkBrowser.Revset...), key.WithHelp(JoinKeys(m.BookmarkBrowser.Revset), "set revset")),
 		},
 		Preview: previewModeKeys[key.Binding]{
 			Mode:         key.NewBinding(key.WithKeys(m.Preview.Mode...), key.WithHelp(JoinKeys(m.Preview.Mode), "preview")),
@@ -95,9 +118,17 @@ func Convert(m KeyMappings[keys]) KeyMappings[key.Binding] {
 			Shrink:       key.NewBinding(key.WithKeys(m.Preview.Shrink...), key.WithHelp(JoinKeys(m.Preview.Shrink), "shrink width")),
 		},
 		Git: gitModeKeys[key.Binding]{
-			Mode:  key.NewBinding(key.WithKeys(m.Git.Mode...), key.WithHelp(JoinKeys(m.Git.Mode), "git")),
-			Push:  key.NewBinding(key.WithKeys(m.Git.Push...), key.WithHelp(JoinKeys(m.Git.Push), "git push")),
-			Fetch: key.NewBinding(key.WithKeys(m.Git.Fetch...), key.WithHelp(JoinKeys(m.Git.Fetch), "git fetch")),
+			Mode:    key.NewBinding(key.WithKeys(m.Git.Mode...), key.WithHelp(JoinKeys(m.Git.Mode), "git")),
+			Push:    key.NewBinding(key.WithKeys(m.Git.Push...), key.WithHelp(JoinKeys(m.Git.Push), "git push")),
+			Fetch:   key.NewBinding(key.WithKeys(m.Git.Fetch...), key.WithHelp(JoinKeys(m.Git.Fetch), "git fetch")),
+			Remotes: key.NewBinding(key.WithKeys(m.Git.Remotes...), key.WithHelp(JoinKeys(m.Git.Remotes), "remotes")),
+			Sync:    key.NewBinding(key.WithKeys(m.Git.Sync...), key.WithHelp(JoinKeys(m.Git.Sync), "sync stacks")),
+		},
+		GitRemote: gitRemoteModeKeys[key.Binding]{
+			Add:    key.NewBinding(key.WithKeys(m.GitRemote.Add...), key.WithHelp(JoinKeys(m.GitRemote.Add), "add")),
+			Remove: key.NewBinding(key.WithKeys(m.GitRemote.Remove...), key.WithHelp(JoinKeys(m.GitRemote.Remove), "remove")),
+			Rename: key.NewBinding(key.WithKeys(m.GitRemote.Rename...), key.WithHelp(JoinKeys(m.GitRemote.Rename), "rename")),
+			SetUrl: key.NewBinding(key.WithKeys(m.GitRemote.SetUrl...), key.WithHelp(JoinKeys(m.GitRemote.SetUrl), "set url")),
 		},
 		OpLog: opLogModeKeys[key.Binding]{
 			Mode:    key.NewBinding(key.WithKeys(m.OpLog.Mode...), key.WithHelp(JoinKeys(m.OpLog.Mode), "oplog")),
@@ -107,6 +138,42 @@ func Convert(m KeyMappings[keys]) KeyMappings[key.Binding] {
 			Mode:   key.NewBinding(key.WithKeys(m.InlineDescribe.Mode...), key.WithHelp(JoinKeys(m.InlineDescribe.Mode), "inline describe")),
 			Accept: key.NewBinding(key.WithKeys(m.InlineDescribe.Accept...), key.WithHelp(JoinKeys(m.InlineDescribe.Accept), "accept")),
 		},
+		MetaEdit: metaEditModeKeys[key.Binding]{
+			Mode:   key.NewBinding(key.WithKeys(m.MetaEdit.Mode...), key.WithHelp(JoinKeys(m.MetaEdit.Mode), "edit metadata")),
+			Next:   key.NewBinding(key.WithKeys(m.MetaEdit.Next...), key.WithHelp(JoinKeys(m.MetaEdit.Next), "next field")),
+			Prev:   key.NewBinding(key.WithKeys(m.MetaEdit.Prev...), key.WithHelp(JoinKeys(m.MetaEdit.Prev), "previous field")),
+			Toggle: key.NewBinding(key.WithKeys(m.MetaEdit.Toggle...), key.WithHelp(JoinKeys(m.MetaEdit.Toggle), "toggle")),
+		},
+		Run: runModeKeys[key.Binding]{
+			Mode:  key.NewBinding(key.WithKeys(m.Run.Mode...), key.WithHelp(JoinKeys(m.Run.Mode), "run on stack")),
+			Log:   key.NewBinding(key.WithKeys(m.Run.Log...), key.WithHelp(JoinKeys(m.Run.Log), "show log")),
+			Rerun: key.NewBinding(key.WithKeys(m.Run.Rerun...), key.WithHelp(JoinKeys(m.Run.Rerun), "rerun")),
+		},
+		Tag: tagModeKeys[key.Binding]{
+			Mode:   key.NewBinding(key.WithKeys(m.Tag.Mode...), key.WithHelp(JoinKeys(m.Tag.Mode), "tags")),
+			Set:    key.NewBinding(key.WithKeys(m.Tag.Set...), key.WithHelp(JoinKeys(m.Tag.Set), "set tag")),
+			Delete: key.NewBinding(key.WithKeys(m.Tag.Delete...), key.WithHelp(JoinKeys(m.Tag.Delete), "delete")),
+			Jump:   key.NewBinding(key.WithKeys(m.Tag.Jump...), key.WithHelp(JoinKeys(m.Tag.Jump), "jump")),
+			Revset: key.NewBinding(key.WithKeys(m.Tag.Revset...), key.WithHelp(JoinKeys(m.Tag.Revset), "show tags()")),
+		},
+		Divergence: divergenceModeKeys[key.Binding]{
+			Mode:        key.NewBinding(key.WithKeys(m.Divergence.Mode...), key.WithHelp(JoinKeys(m.Divergence.Mode), "divergent changes")),
+			Next:        key.NewBinding(key.WithKeys(m.Divergence.Next...), key.WithHelp(JoinKeys(m.Divergence.Next), "next commit")),
+			Prev:        key.NewBinding(key.WithKeys(m.Divergence.Prev...), key.WithHelp(JoinKeys(m.Divergence.Prev), "previous commit")),
+			Abandon:     key.NewBinding(key.WithKeys(m.Divergence.Abandon...), key.WithHelp(JoinKeys(m.Divergence.Abandon), "abandon")),
+			Squash:      key.NewBinding(key.WithKeys(m.Divergence.Squash...), key.WithHelp(JoinKeys(m.Divergence.Squash), "squash others into")),
+			NewChangeId: key.NewBinding(key.WithKeys(m.Divergence.NewChangeId...), key.WithHelp(JoinKeys(m.Divergence.NewChangeId), "new change id")),
+		},
+		Submit: submitModeKeys[key.Binding]{
+			Mode: key.NewBinding(key.WithKeys(m.Submit.Mode...), key.WithHelp(JoinKeys(m.Submit.Mode), "submit for review")),
+		},
+		CommandLog: commandLogModeKeys[key.Binding]{
+			Mode:   key.NewBinding(key.WithKeys(m.CommandLog.Mode...), key.WithHelp(JoinKeys(m.CommandLog.Mode), "command log")),
+			Rerun:  key.NewBinding(key.WithKeys(m.CommandLog.Rerun...), key.WithHelp(JoinKeys(m.CommandLog.Rerun), "rerun")),
+			Copy:   key.NewBinding(key.WithKeys(m.CommandLog.Copy...), key.WithHelp(JoinKeys(m.CommandLog.Copy), "copy")),
+			Output: key.NewBinding(key.WithKeys(m.CommandLog.Output...), key.WithHelp(JoinKeys(m.CommandLog.Output), "full output")),
+			All:    key.NewBinding(key.WithKeys(m.CommandLog.All...), key.WithHelp(JoinKeys(m.CommandLog.All), "show all")),
+		},
 		FileSearch: fileSearchKeys[key.Binding]{
 			Toggle: key.NewBinding(key.WithKeys(m.FileSearch.Toggle...), key.WithHelp(JoinKeys(m.FileSearch.Toggle), "fuzzy files search")),
 			Up:     key.NewBinding(key.WithKeys(m.FileSearch.Up...), key.WithHelp(JoinKeys(m.FileSearch.Up), "up")),
@@ -138,50 +205,114 @@ func JoinKeys(keys []string) string {
 	return strings.Join(joined, "/")
 }
 
+// KeySequence is a binding of keys pressed one after the other. It is configured as the keys
+// separated by spaces, e.g. "g g", and matches the key message whose string is the whole sequence.
+type KeySequence struct {
+	Key  string
+	Keys []string
+	Help string
+}
+
+// SplitKeySequence returns the keys of a key sequence, or nil when the key is a single key
+func SplitKeySequence(k string) []string {
+	fields := strings.Fields(k)
+	if len(fields) < 2 {
+		return nil
+	}
+	for i, field := range fields {
+		if field == "space" {
+			fields[i] = " "
+		}
+	}
+	return fields
+}
+
+// KeySequences returns the key sequences of all the bindings of the key map
+func KeySequences(m KeyMappings[key.Binding]) []KeySequence {
+	var sequences []KeySequence
+	var collect func(v reflect.Value)
+	collect = func(v reflect.Value) {
+		if binding, ok := v.Interface().(key.Binding); ok {
+			for _, k := range binding.Keys() {
+				if keys := SplitKeySequence(k); keys != nil {
+					sequences = append(sequences, KeySequence{Key: k, Keys: keys, Help: binding.Help().Desc})
+				}
+			}
+			return
+		}
+		if v.Kind() == reflect.Struct {
+			for i := range v.NumField() {
+				collect(v.Field(i))
+			}
+		}
+	}
+	collect(reflect.ValueOf(m))
+	return sequences
+}
+
 type keys []string
 
 type KeyMappings[T any] struct {
-	Up                T                         `toml:"up"`
-	Down              T                         `toml:"down"`
-	JumpToParent      T                         `toml:"jump_to_parent"`
-	JumpToChildren    T                         `toml:"jump_to_children"`
-	JumpToWorkingCopy T                         `toml:"jump_to_working_copy"`
-	Apply             T                         `toml:"apply"`
-	Cancel            T                         `toml:"cancel"`
-	ToggleSelect      T                         `toml:"toggle_select"`
-	New               T                         `toml:"new"`
-	Commit            T                         `toml:"commit"`
-	Refresh           T                         `toml:"refresh"`
-	Abandon           T                         `toml:"abandon"`
-	Diff              T                         `toml:"diff"`
-	Quit              T                         `toml:"quit"`
-	Help              T                         `toml:"help"`
-	Describe          T                         `toml:"describe"`
-	Edit              T                         `toml:"edit"`
-	Diffedit          T                         `toml:"diffedit"`
-	Absorb            T                         `toml:"absorb"`
-	Split             T                         `toml:"split"`
-	Undo              T                         `toml:"undo"`
-	Revset            T                         `toml:"revset"`
-	ExecJJ            T                         `toml:"exec_jj"`
-	ExecShell         T                         `toml:"exec_shell"`
-	AceJump           T                         `toml:"ace_jump"`
-	QuickSearch       T                         `toml:"quick_search"`
-	QuickSearchCycle  T                         `toml:"quick_search_cycle"`
-	CustomCommands    T                         `toml:"custom_commands"`
-	Leader            T                         `toml:"leader"`
-	Suspend           T                         `toml:"suspend"`
-	Rebase            rebaseModeKeys[T]         `toml:"rebase"`
-	Duplicate         duplicateModeKeys[T]      `toml:"duplicate"`
-	Squash            squashModeKeys[T]         `toml:"squash"`
-	Details           detailsModeKeys[T]        `toml:"details"`
-	Evolog            evologModeKeys[T]         `toml:"evolog"`
-	Preview           previewModeKeys[T]        `toml:"preview"`
-	Bookmark          bookmarkModeKeys[T]       `toml:"bookmark"`
-	InlineDescribe    inlineDescribeModeKeys[T] `toml:"inline_describe"`
-	Git               gitModeKeys[T]            `toml:"git"`
-	OpLog             opLogModeKeys[T]          `toml:"oplog"`
-	FileSearch        fileSearchKeys[T]         `toml:"file_search"`
+	// Preset is the name of the built-in keymap the configured keys are layered on
+	Preset            string                     `toml:"preset"`
+	Up                T                          `toml:"up"`
+	Down              T                          `toml:"down"`
+	JumpToParent      T                          `toml:"jump_to_parent"`
+	JumpToChildren    T                          `toml:"jump_to_children"`
+	JumpToWorkingCopy T                          `toml:"jump_to_working_copy"`
+	JumpToTop         T                          `toml:"jump_to_top"`
+	JumpToBottom      T                          `toml:"jump_to_bottom"`
+	HalfPageDown      T                          `toml:"half_page_down"`
+	HalfPageUp        T                          `toml:"half_page_up"`
+	Apply             T                          `toml:"apply"`
+	Cancel            T                          `toml:"cancel"`
+	ToggleSelect      T                          `toml:"toggle_select"`
+	New               T                          `toml:"new"`
+	Commit            T                          `toml:"commit"`
+	Refresh           T                          `toml:"refresh"`
+	Abandon           T                          `toml:"abandon"`
+	Diff              T                          `toml:"diff"`
+	Quit              T                          `toml:"quit"`
+	Help              T                          `toml:"help"`
+	Describe          T                          `toml:"describe"`
+	Edit              T                          `toml:"edit"`
+	Diffedit          T                          `toml:"diffedit"`
+	Absorb            T                          `toml:"absorb"`
+	Split             T                          `toml:"split"`
+	Undo              T                          `toml:"undo"`
+	Revset            T                          `toml:"revset"`
+	ExecJJ            T                          `toml:"exec_jj"`
+	ExecShell         T                          `toml:"exec_shell"`
+	AceJump           T                          `toml:"ace_jump"`
+	QuickSearch       T                          `toml:"quick_search"`
+	QuickSearchCycle  T                          `toml:"quick_search_cycle"`
+	CustomCommands    T                          `toml:"custom_commands"`
+	CommandPalette    T                          `toml:"command_palette"`
+	MacroRecord       T                          `toml:"macro_record"`
+	MacroReplay       T                          `toml:"macro_replay"`
+	Leader            T                          `toml:"leader"`
+	Suspend           T                          `toml:"suspend"`
+	CancelCommand     T                          `toml:"cancel_command"`
+	Rebase            rebaseModeKeys[T]          `toml:"rebase"`
+	Duplicate         duplicateModeKeys[T]       `toml:"duplicate"`
+	Squash            squashModeKeys[T]          `toml:"squash"`
+	Details           detailsModeKeys[T]         `toml:"details"`
+	Evolog            evologModeKeys[T]          `toml:"evolog"`
+	Preview           previewModeKeys[T]         `toml:"preview"`
+	Bookmark          bookmarkModeKeys[T]        `toml:"bookmark"`
+	BookmarkCleanup   bookmarkCleanupModeKeys[T] `toml:"bookmark_cleanup"`
+	BookmarkBrowser   bookmarkBrowserModeKeys[T] `toml:"bookmark_browser"`
+	InlineDescribe    inlineDescribeModeKeys[T]  `toml:"inline_describe"`
+	Git               gitModeKeys[T]             `toml:"git"`
+	GitRemote         gitRemoteModeKeys[T]       `toml:"git_remote"`
+	OpLog             opLogModeKeys[T]           `toml:"oplog"`
+	FileSearch        fileSearchKeys[T]          `toml:"file_search"`
+	Run               runModeKeys[T]             `toml:"run"`
+	MetaEdit          metaEditModeKeys[T]        `toml:"metaedit"`
+	Divergence        divergenceModeKeys[T]      `toml:"divergence"`
+	Tag               tagModeKeys[T]             `toml:"tag"`
+	Submit            submitModeKeys[T]          `toml:"submit"`
+	CommandLog        commandLogModeKeys[T]      `toml:"command_log"`
 }
 
 type bookmarkModeKeys[T any] struct {
@@ -192,6 +323,23 @@ type bookmarkModeKeys[T any] struct {
 	Forget  T `toml:"forget"`
 	Track   T `toml:"track"`
 	Untrack T `toml:"untrack"`
+	Cleanup T `toml:"cleanup"`
+	Browse  T `toml:"browse"`
+	Stack   T `toml:"stack"`
+}
+
+type bookmarkCleanupModeKeys[T any] struct {
+	ToggleAll T `toml:"toggle_all"`
+	Delete    T `toml:"delete"`
+	Forget    T `toml:"forget"`
+	Push      T `toml:"push"`
+}
+
+type bookmarkBrowserModeKeys[T any] struct {
+	Mode   T `toml:"mode"`
+	Sort   T `toml:"sort"`
+	Filter T `toml:"filter"`
+	Revset T `toml:"revset"`
 }
 
 type squashModeKeys[T any] struct {
@@ -237,9 +385,18 @@ type detailsModeKeys[T any] struct {
 }
 
 type gitModeKeys[T any] struct {
-	Mode  T `toml:"mode"`
-	Push  T `toml:"push"`
-	Fetch T `toml:"fetch"`
+	Mode    T `toml:"mode"`
+	Push    T `toml:"push"`
+	Fetch   T `toml:"fetch"`
+	Remotes T `toml:"remotes"`
+	Sync    T `toml:"sync"`
+}
+
+type gitRemoteModeKeys[T any] struct {
+	Add    T `toml:"add"`
+	Remove T `toml:"remove"`
+	Rename T `toml:"rename"`
+	SetUrl T `toml:"set_url"`
 }
 
 type previewModeKeys[T any] struct {
@@ -263,6 +420,48 @@ type inlineDescribeModeKeys[T any] struct {
 	Accept T `toml:"accept"`
 }
 
+type metaEditModeKeys[T any] struct {
+	Mode   T `toml:"mode"`
+	Next   T `toml:"next"`
+	Prev   T `toml:"prev"`
+	Toggle T `toml:"toggle"`
+}
+
+type runModeKeys[T any] struct {
+	Mode  T `toml:"mode"`
+	Log   T `toml:"log"`
+	Rerun T `toml:"rerun"`
+}
+
+type tagModeKeys[T any] struct {
+	Mode   T `toml:"mode"`
+	Set    T `toml:"set"`
+	Delete T `toml:"delete"`
+	Jump   T `toml:"jump"`
+	Revset T `toml:"revset"`
+}
+
+type divergenceModeKeys[T any] struct {
+	Mode        T `toml:"mode"`
+	Next        T `toml:"next"`
+	Prev        T `toml:"prev"`
+	Abandon     T `toml:"abandon"`
+	Squash      T `toml:"squash"`
+	NewChangeId T `toml:"new_change_id"`
+}
+
+type submitModeKeys[T any] struct {
+	Mode T `toml:"mode"`
+}
+
+type commandLogModeKeys[T any] struct {
+	Mode   T `toml:"mode"`
+	Rerun  T `toml:"rerun"`
+	Copy   T `toml:"copy"`
+	Output T `toml:"output"`
+	All    T `toml:"all"`
+}
+
 type fileSearchKeys[T any] struct {
 	Toggle T `toml:"toggle"`
 	Up     T `toml:"up"`
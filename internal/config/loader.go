@@ -6,6 +6,7 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 )
@@ -67,6 +68,22 @@ func loadDefaultConfig() *Config {
 func (c *Config) Load(data string) error {
 	var err error
 
+	// the keys of the preset are loaded first so that the configured keys override them
+	var preset struct {
+		Keys struct {
+			Preset string `toml:"preset"`
+		} `toml:"keys"`
+	}
+	_, err = toml.Decode(data, &preset)
+	if err != nil {
+		return err
+	}
+	if preset.Keys.Preset != "" {
+		if err = c.loadKeyPreset(preset.Keys.Preset); err != nil {
+			return err
+		}
+	}
+
 	_, err = toml.Decode(data, c)
 	if err != nil {
 		return err
@@ -75,6 +92,25 @@ func (c *Config) Load(data string) error {
 	return nil
 }
 
+// KeyPresets returns the names of the built-in keymaps which can be set as `keys.preset`
+func KeyPresets() []string {
+	entries, _ := configFS.ReadDir("default/presets")
+	var presets []string
+	for _, entry := range entries {
+		presets = append(presets, strings.TrimSuffix(entry.Name(), ".toml"))
+	}
+	return presets
+}
+
+func (c *Config) loadKeyPreset(name string) error {
+	data, err := configFS.ReadFile("default/presets/" + name + ".toml")
+	if err != nil {
+		return fmt.Errorf("unknown keys.preset %q, available presets are %s", name, strings.Join(KeyPresets(), ", "))
+	}
+	_, err = toml.Decode(string(data), c)
+	return err
+}
+
 func LoadConfigFile() ([]byte, error) {
 	configFile := getConfigFilePath()
 	_, err := os.Stat(configFile)
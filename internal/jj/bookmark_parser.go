@@ -1,12 +1,17 @@
 package jj
 
 import (
+	"slices"
+	"strconv"
 	"strings"
 )
 
 const (
 	moveBookmarkTemplate = `separate(";", name, if(remote, "remote", "."), tracked, conflict, normal_target.contained_in("%s"), normal_target.commit_id().shortest(1)) ++ "\n"`
 	allBookmarkTemplate  = `separate(";", name, if(remote, remote, "."), tracked, conflict, 'false', normal_target.commit_id().shortest(1)) ++ "\n"`
+	// merged is true for bookmarks pointing to an ancestor of trunk(), excluding trunk() itself
+	staleBookmarkTemplate = `separate(";", name, if(remote, remote, "."), tracked, present, if(normal_target, normal_target.contained_in("::trunk() ~ trunk()"), false), if(normal_target, normal_target.committer().timestamp().ago(), "-")) ++ "\n"`
+	bookmarkRefTemplate   = `name ++ ";" ++ if(remote, remote, ".") ++ ";" ++ tracked ++ ";" ++ conflict ++ ";" ++ present ++ ";" ++ if(normal_target, normal_target.change_id().shortest(8), "") ++ ";" ++ if(tracked, tracking_ahead_count().lower(), "0") ++ ";" ++ if(tracked, tracking_behind_count().lower(), "0") ++ ";" ++ if(normal_target, normal_target.committer().timestamp().format("%s"), "0") ++ ";" ++ if(normal_target, normal_target.committer().timestamp().ago(), "") ++ "\n"`
 )
 
 type BookmarkRemote struct {
@@ -97,3 +102,122 @@ func ParseBookmarkListOutput(output string) []Bookmark {
 	}
 	return bookmarks
 }
+
+type StaleBookmark struct {
+	Name     string
+	Age      string
+	Reason   string
+	HasLocal bool
+	Remotes  []string
+}
+
+// ParseStaleBookmarkListOutput returns the bookmarks that are merged into trunk(), that were
+// deleted on a remote they track, or that refer to a remote which is no longer in the given list
+// of remotes. A tracked remote bookmark without a target is a bookmark deleted on the remote, once
+// its deletion is fetched.
+func ParseStaleBookmarkListOutput(output string, remotes []string) []StaleBookmark {
+	bookmarkMap := make(map[string]*StaleBookmark)
+	var orderedNames []string
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(line, ";")
+		if len(parts) < 6 {
+			continue
+		}
+		name, remote, tracked, present, merged, age := parts[0], parts[1], parts[2] == "true", parts[3] == "true", parts[4] == "true", parts[5]
+		if remote == "git" {
+			continue
+		}
+		bookmark, exists := bookmarkMap[name]
+		if !exists {
+			bookmark = &StaleBookmark{Name: name, Age: age}
+			bookmarkMap[name] = bookmark
+			orderedNames = append(orderedNames, name)
+		}
+		if remote == "." {
+			bookmark.HasLocal = present
+			bookmark.Age = age
+			if present && merged {
+				bookmark.Reason = "merged into trunk()"
+			}
+			continue
+		}
+		if tracked && !slices.Contains(bookmark.Remotes, remote) {
+			bookmark.Remotes = append(bookmark.Remotes, remote)
+		}
+		switch {
+		case bookmark.Reason != "":
+		case !slices.Contains(remotes, remote):
+			bookmark.Reason = "remote " + remote + " was removed"
+		case tracked && !present:
+			bookmark.Reason = "deleted on " + remote
+		}
+	}
+
+	var bookmarks []StaleBookmark
+	for _, name := range orderedNames {
+		if b := bookmarkMap[name]; b.Reason != "" {
+			bookmarks = append(bookmarks, *b)
+		}
+	}
+	return bookmarks
+}
+
+// BookmarkRef is a local or a remote bookmark as listed in the bookmark browser
+type BookmarkRef struct {
+	Name     string
+	Remote   string
+	Tracked  bool
+	Conflict bool
+	Present  bool
+	ChangeId string
+	// number of commits the local bookmark is ahead/behind of this tracked remote bookmark
+	Ahead     int
+	Behind    int
+	Timestamp int64
+	Age       string
+}
+
+func (b BookmarkRef) IsLocal() bool {
+	return b.Remote == ""
+}
+
+// Symbol returns the name of the bookmark as used in revsets
+func (b BookmarkRef) Symbol() string {
+	if b.IsLocal() {
+		return b.Name
+	}
+	return b.Name + "@" + b.Remote
+}
+
+func ParseBookmarkRefListOutput(output string) []BookmarkRef {
+	var refs []BookmarkRef
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(line, ";")
+		if len(parts) < 10 {
+			continue
+		}
+		remote := parts[1]
+		if remote == "git" {
+			continue
+		}
+		if remote == "." {
+			remote = ""
+		}
+		ref := BookmarkRef{
+			Name:     parts[0],
+			Remote:   remote,
+			Tracked:  parts[2] == "true",
+			Conflict: parts[3] == "true",
+			Present:  parts[4] == "true",
+			ChangeId: parts[5],
+			Age:      parts[9],
+		}
+		// the counts of a remote bookmark are relative to its local bookmark,
+		// so a remote which is ahead means the local bookmark is behind
+		ref.Behind, _ = strconv.Atoi(parts[6])
+		ref.Ahead, _ = strconv.Atoi(parts[7])
+		ref.Timestamp, _ = strconv.ParseInt(parts[8], 10, 64)
+		refs = append(refs, ref)
+	}
+	return refs
+}
@@ -92,3 +92,39 @@ feature;origin;true;false;false;b`,
 		})
 	}
 }
+
+func TestParseStaleBookmarkListOutput(t *testing.T) {
+	output := `feature;.;false;true;true;2 months ago
+feature;origin;true;true;true;2 months ago
+feature;git;true;true;true;2 months ago
+main;.;false;true;false;1 hour ago
+main;origin;true;true;false;1 hour ago
+wip;.;false;true;false;3 days ago
+old;.;false;true;false;1 year ago
+old;fork;true;true;false;1 year ago
+gone;.;false;true;false;5 days ago
+gone;origin;true;false;false;-
+untracked;origin;false;false;false;-`
+	bookmarks := ParseStaleBookmarkListOutput(output, []string{"origin"})
+	assert.Equal(t, []StaleBookmark{
+		{Name: "feature", Age: "2 months ago", Reason: "merged into trunk()", HasLocal: true, Remotes: []string{"origin"}},
+		{Name: "old", Age: "1 year ago", Reason: "remote fork was removed", HasLocal: true, Remotes: []string{"fork"}},
+		{Name: "gone", Age: "5 days ago", Reason: "deleted on origin", HasLocal: true, Remotes: []string{"origin"}},
+	}, bookmarks)
+}
+
+func TestParseBookmarkRefListOutput(t *testing.T) {
+	output := `feature;.;false;false;true;kmtnvwpx;0;0;1700000000;2 days ago
+feature;origin;true;false;true;zqrsxlyo;1;3;1690000000;4 months ago
+feature;git;true;false;true;kmtnvwpx;0;0;1700000000;2 days ago
+conflicted;.;false;true;true;;0;0;0;`
+	refs := ParseBookmarkRefListOutput(output)
+	assert.Len(t, refs, 3)
+	assert.True(t, refs[0].IsLocal())
+	assert.Equal(t, "feature", refs[0].Symbol())
+	assert.Equal(t, "feature@origin", refs[1].Symbol())
+	assert.Equal(t, 3, refs[1].Ahead)
+	assert.Equal(t, 1, refs[1].Behind)
+	assert.Equal(t, int64(1690000000), refs[1].Timestamp)
+	assert.True(t, refs[2].Conflict)
+}
@@ -2,6 +2,7 @@ package jj
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -78,6 +79,19 @@ func SetDescription(revision string, description string) CommandArgs {
 	return []string{"describe", "-r", revision, "-m", description}
 }
 
+func GetAuthor(revision string) CommandArgs {
+	return []string{"log", "-r", revision, "--template", `author.name() ++ "\n" ++ author.email()`, "--no-graph", "--ignore-working-copy", "--color", "never", "--quiet"}
+}
+
+func MetaEdit(revisions SelectedRevisions, flags ...string) CommandArgs {
+	args := []string{"metaedit"}
+	args = append(args, revisions.AsArgs()...)
+	if flags != nil {
+		args = append(args, flags...)
+	}
+	return args
+}
+
 func GetDescription(revision string) CommandArgs {
 	return []string{"log", "-r", revision, "--template", "description", "--no-graph", "--ignore-working-copy", "--color", "never", "--quiet"}
 }
@@ -127,8 +141,12 @@ func Status(revision string) CommandArgs {
 	return []string{"log", "-r", revision, "--summary", "--no-graph", "--color", "never", "--quiet", "--template", template, "--ignore-working-copy"}
 }
 
-func BookmarkSet(revision string, name string) CommandArgs {
-	return []string{"bookmark", "set", "-r", revision, name}
+func BookmarkSet(revision string, name string, extraFlags ...string) CommandArgs {
+	args := []string{"bookmark", "set", "-r", revision, name}
+	if extraFlags != nil {
+		args = append(args, extraFlags...)
+	}
+	return args
 }
 
 func BookmarkMove(revision string, bookmark string, extraFlags ...string) CommandArgs {
@@ -139,12 +157,12 @@ func BookmarkMove(revision string, bookmark string, extraFlags ...string) Comman
 	return args
 }
 
-func BookmarkDelete(name string) CommandArgs {
-	return []string{"bookmark", "delete", name}
+func BookmarkDelete(names ...string) CommandArgs {
+	return append([]string{"bookmark", "delete"}, names...)
 }
 
-func BookmarkForget(name string) CommandArgs {
-	return []string{"bookmark", "forget", name}
+func BookmarkForget(names ...string) CommandArgs {
+	return append([]string{"bookmark", "forget"}, names...)
 }
 
 func BookmarkTrack(name string) CommandArgs {
@@ -185,6 +203,26 @@ func BookmarkListAll() CommandArgs {
 	return []string{"bookmark", "list", "-a", "--template", allBookmarkTemplate, "--color", "never", "--ignore-working-copy"}
 }
 
+func TagList() CommandArgs {
+	return []string{"tag", "list", "--template", tagListTemplate, "--color", "never", "--ignore-working-copy"}
+}
+
+func TagSet(revision string, name string) CommandArgs {
+	return []string{"tag", "set", "-r", revision, name}
+}
+
+func TagDelete(name string) CommandArgs {
+	return []string{"tag", "delete", name}
+}
+
+func BookmarkListRefs() CommandArgs {
+	return []string{"bookmark", "list", "-a", "--template", bookmarkRefTemplate, "--color", "never", "--ignore-working-copy"}
+}
+
+func BookmarkListStale() CommandArgs {
+	return []string{"bookmark", "list", "-a", "--template", staleBookmarkTemplate, "--color", "never", "--ignore-working-copy"}
+}
+
 func GitFetch(flags ...string) CommandArgs {
 	args := []string{"git", "fetch"}
 	if flags != nil {
@@ -201,6 +239,26 @@ func GitPush(flags ...string) CommandArgs {
 	return args
 }
 
+func GitRemoteList() CommandArgs {
+	return []string{"git", "remote", "list", "--color", "never", "--ignore-working-copy"}
+}
+
+func GitRemoteAdd(name string, url string) CommandArgs {
+	return []string{"git", "remote", "add", name, url}
+}
+
+func GitRemoteRemove(name string) CommandArgs {
+	return []string{"git", "remote", "remove", name}
+}
+
+func GitRemoteRename(oldName string, newName string) CommandArgs {
+	return []string{"git", "remote", "rename", oldName, newName}
+}
+
+func GitRemoteSetUrl(name string, url string) CommandArgs {
+	return []string{"git", "remote", "set-url", name, url}
+}
+
 func Show(revision string, extraArgs ...string) CommandArgs {
 	args := []string{"show", "-r", revision, "--color", "always", "--ignore-working-copy"}
 	if extraArgs != nil {
@@ -310,10 +368,37 @@ func FilesInRevision(revision *Commit) CommandArgs {
 	return args
 }
 
+func GetCommitIdsFromRevset(revset string) CommandArgs {
+	return []string{"log", "-r", revset, "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--template", "commit_id ++ '\n'"}
+}
+
+// GetChangeIdsOfCommits lists the `commit_id;change_id` pairs of the revisions with their full ids
+func GetChangeIdsOfCommits(revset string) CommandArgs {
+	return []string{"log", "-r", revset, "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--template", "commit_id ++ ';' ++ change_id ++ '\n'"}
+}
+
 func GetIdsFromRevset(revset string) CommandArgs {
 	return []string{"log", "-r", revset, "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--template", "change_id.shortest() ++ '\n'"}
 }
 
+// Stack lists the revisions between trunk() and the revision, skipping empty revisions without a description
+func Stack(revision string) CommandArgs {
+	revset := fmt.Sprintf(`trunk()..%s ~ (empty() & description(exact:""))`, revision)
+	return []string{"log", "-r", revset, "--reversed", "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--template", stackTemplate}
+}
+
+func Divergent() CommandArgs {
+	return []string{"log", "-r", "divergent()", "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--template", divergentTemplate}
+}
+
+func WorkspaceAdd(name string, revision string, path string) CommandArgs {
+	return []string{"workspace", "add", "--name", name, "-r", revision, path}
+}
+
+func WorkspaceForget(name string) CommandArgs {
+	return []string{"workspace", "forget", name}
+}
+
 func escapeFileName(fileName string) string {
 	// Escape backslashes and quotes in the file name for shell compatibility
 	if strings.Contains(fileName, "\\") {
@@ -324,3 +409,50 @@ func escapeFileName(fileName string) string {
 	}
 	return fmt.Sprintf("file:\"%s\"", fileName)
 }
+
+// readOnlySubcommands are the commands, or the subcommands of a command group, which don't change
+// the repo other than snapshotting the working copy
+var readOnlySubcommands = map[string][]string{
+	"log":       nil,
+	"show":      nil,
+	"diff":      nil,
+	"interdiff": nil,
+	"status":    nil,
+	"st":        nil,
+	"evolog":    nil,
+	"root":      nil,
+	"help":      nil,
+	"version":   nil,
+	"file":      {"list", "show", "annotate"},
+	"op":        {"log", "show", "diff"},
+	"operation": {"log", "show", "diff"},
+	"bookmark":  {"list", "l"},
+	"b":         {"list", "l"},
+	"tag":       {"list", "l"},
+	"config":    {"get", "list", "path"},
+	"workspace": {"list", "root"},
+	"git":       {"remote"},
+}
+
+// IsReadOnly reports whether the command only reads from the repo, so that it can run
+// while other commands are changing it
+func (a CommandArgs) IsReadOnly() bool {
+	if len(a) == 0 {
+		return false
+	}
+	subcommands, ok := readOnlySubcommands[a[0]]
+	if !ok {
+		return false
+	}
+	if subcommands == nil {
+		return true
+	}
+	if len(a) < 2 || !slices.Contains(subcommands, a[1]) {
+		return false
+	}
+	// `jj git remote list` is the only read-only remote subcommand
+	if a[0] == "git" {
+		return len(a) > 2 && a[2] == "list"
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package jj
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandArgs_IsReadOnly(t *testing.T) {
+	tests := []struct {
+		args CommandArgs
+		want bool
+	}{
+		{Log("::@", 0), true},
+		{Show("abc"), true},
+		{BookmarkListAll(), true},
+		{GitRemoteList(), true},
+		{OpLog(10), true},
+		{Args("file", "show", "-r", "abc", "README.md"), true},
+		{Args("file", "track", "README.md"), false},
+		{GitFetch(), false},
+		{GitPush(), false},
+		{GitRemoteAdd("origin", "url"), false},
+		{BookmarkSet("abc", "main"), false},
+		{OpRestore("abc"), false},
+		{Args(), false},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.args.IsReadOnly())
+		})
+	}
+}
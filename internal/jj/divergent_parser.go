@@ -0,0 +1,47 @@
+package jj
+
+import "strings"
+
+// the fields are joined without separate() which drops the empty ones, e.g. a missing description
+const divergentTemplate = `change_id.short() ++ ";" ++ commit_id.short() ++ ";" ++ author.email() ++ ";" ++ committer.timestamp().ago() ++ ";" ++ description.first_line() ++ "\n"`
+
+type DivergentChange struct {
+	ChangeId string
+	Commits  []*DivergentCommit
+}
+
+type DivergentCommit struct {
+	Commit      *Commit
+	Author      string
+	Timestamp   string
+	Description string
+}
+
+// ParseDivergentOutput groups the commits in the output of `Divergent` by their change id,
+// keeping the order in which the changes were listed.
+func ParseDivergentOutput(output string) []*DivergentChange {
+	var changes []*DivergentChange
+	changeMap := make(map[string]*DivergentChange)
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(line, ";", 5)
+		if len(parts) < 5 {
+			continue
+		}
+		changeId := parts[0]
+		change, ok := changeMap[changeId]
+		if !ok {
+			change = &DivergentChange{ChangeId: changeId}
+			changeMap[changeId] = change
+			changes = append(changes, change)
+		}
+		change.Commits = append(change.Commits, &DivergentCommit{
+			// divergent change ids are marked the same way as in the log so that
+			// commands address each commit by its commit id
+			Commit:      &Commit{ChangeId: changeId + "??", CommitId: parts[1]},
+			Author:      parts[2],
+			Timestamp:   parts[3],
+			Description: parts[4],
+		})
+	}
+	return changes
+}
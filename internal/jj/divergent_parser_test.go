@@ -0,0 +1,40 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDivergentOutput(t *testing.T) {
+	output := `kmtnvwpx;0a1b2c3d;jane@example.com;2 hours ago;fix: handle empty input
+kmtnvwpx;4e5f6a7b;jane@example.com;3 hours ago;fix: handle empty input; again
+zqrsxlyo;8c9d0e1f;john@example.com;1 day ago;
+zqrsxlyo;2a3b4c5d;john@example.com;2 days ago;docs`
+	changes := ParseDivergentOutput(output)
+	assert.Len(t, changes, 2)
+
+	assert.Equal(t, "kmtnvwpx", changes[0].ChangeId)
+	assert.Len(t, changes[0].Commits, 2)
+	assert.Equal(t, "4e5f6a7b", changes[0].Commits[1].Commit.GetChangeId())
+	assert.Equal(t, "fix: handle empty input; again", changes[0].Commits[1].Description)
+
+	assert.Equal(t, "zqrsxlyo", changes[1].ChangeId)
+	assert.Equal(t, "", changes[1].Commits[0].Description)
+}
+
+func TestParseDivergentOutput_EmptyFields(t *testing.T) {
+	// the output of divergentTemplate for a commit without an author email and a description
+	output := "zqrsxlyo;8c9d0e1f;;1 day ago;\n" +
+		"zqrsxlyo;2a3b4c5d;john@example.com;2 days ago;docs\n"
+	changes := ParseDivergentOutput(output)
+	assert.Len(t, changes, 1)
+	assert.Len(t, changes[0].Commits, 2)
+	assert.Equal(t, "8c9d0e1f", changes[0].Commits[0].Commit.CommitId)
+	assert.Equal(t, "", changes[0].Commits[0].Author)
+	assert.Equal(t, "", changes[0].Commits[0].Description)
+}
+
+func TestParseDivergentOutput_Empty(t *testing.T) {
+	assert.Empty(t, ParseDivergentOutput(""))
+}
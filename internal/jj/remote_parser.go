@@ -0,0 +1,21 @@
+package jj
+
+import "strings"
+
+type Remote struct {
+	Name string
+	URL  string
+}
+
+// ParseRemoteListOutput parses the `<name> <url>` lines printed by `jj git remote list`
+func ParseRemoteListOutput(output string) []Remote {
+	var remotes []Remote
+	for _, line := range strings.Split(output, "\n") {
+		name, url, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if name == "" {
+			continue
+		}
+		remotes = append(remotes, Remote{Name: name, URL: strings.TrimSpace(url)})
+	}
+	return remotes
+}
@@ -0,0 +1,18 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRemoteListOutput(t *testing.T) {
+	output := `origin git@github.com:idursun/jjui.git
+upstream https://github.com/jj-vcs/jj.git
+`
+	remotes := ParseRemoteListOutput(output)
+	assert.Equal(t, []Remote{
+		{Name: "origin", URL: "git@github.com:idursun/jjui.git"},
+		{Name: "upstream", URL: "https://github.com/jj-vcs/jj.git"},
+	}, remotes)
+}
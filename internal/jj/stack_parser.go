@@ -0,0 +1,42 @@
+package jj
+
+import "strings"
+
+const stackTemplate = `change_id.shortest(8) ++ ";" ++ change_id ++ ";" ++ author.email() ++ ";" ++ local_bookmarks.map(|b| b.name()).join(",") ++ ";" ++ description.first_line() ++ "\n"`
+
+// StackCommit is a revision of a stack, listed from the bottom of the stack to its head
+type StackCommit struct {
+	ChangeId     string
+	FullChangeId string
+	Email        string
+	Bookmarks    []string
+	Description  string
+}
+
+// User returns the local part of the author email
+func (c StackCommit) User() string {
+	user, _, _ := strings.Cut(c.Email, "@")
+	return user
+}
+
+func ParseStackOutput(output string) []StackCommit {
+	var commits []StackCommit
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(line, ";", 5)
+		if len(parts) < 5 || parts[0] == "" {
+			continue
+		}
+		var bookmarks []string
+		if parts[3] != "" {
+			bookmarks = strings.Split(parts[3], ",")
+		}
+		commits = append(commits, StackCommit{
+			ChangeId:     parts[0],
+			FullChangeId: parts[1],
+			Email:        parts[2],
+			Bookmarks:    bookmarks,
+			Description:  parts[4],
+		})
+	}
+	return commits
+}
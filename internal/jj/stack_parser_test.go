@@ -0,0 +1,19 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStackOutput(t *testing.T) {
+	output := `kmtnvwpx;kmtnvwpxlzsruqqlnsxpmtywkquqqkuo;jane@example.com;;feat: add parser
+zqrsxlyo;zqrsxlyoqmtlvnwkrnzqkqnrxoxkmlzl;jane@example.com;jane/fix,other;fix: handle ; in descriptions
+`
+	commits := ParseStackOutput(output)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, StackCommit{ChangeId: "kmtnvwpx", FullChangeId: "kmtnvwpxlzsruqqlnsxpmtywkquqqkuo", Email: "jane@example.com", Description: "feat: add parser"}, commits[0])
+	assert.Equal(t, "jane", commits[0].User())
+	assert.Equal(t, []string{"jane/fix", "other"}, commits[1].Bookmarks)
+	assert.Equal(t, "fix: handle ; in descriptions", commits[1].Description)
+}
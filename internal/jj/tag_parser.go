@@ -0,0 +1,32 @@
+package jj
+
+import "strings"
+
+const tagListTemplate = `name ++ ";" ++ if(normal_target, normal_target.change_id().shortest(8), "") ++ ";" ++ if(normal_target, normal_target.description().first_line(), "") ++ "\n"`
+
+type Tag struct {
+	Name        string
+	ChangeId    string
+	Description string
+}
+
+// Conflict reports whether the tag points to more than one revision
+func (t Tag) Conflict() bool {
+	return t.ChangeId == ""
+}
+
+func ParseTagListOutput(output string) []Tag {
+	var tags []Tag
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(line, ";", 3)
+		if len(parts) < 3 || parts[0] == "" {
+			continue
+		}
+		tags = append(tags, Tag{
+			Name:        parts[0],
+			ChangeId:    parts[1],
+			Description: parts[2],
+		})
+	}
+	return tags
+}
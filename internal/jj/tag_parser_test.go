@@ -0,0 +1,19 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTagListOutput(t *testing.T) {
+	output := `v0.1.0;kmtnvwpx;release: 0.1.0
+v0.2.0;zqrsxlyo;release: 0.2.0; with notes
+conflicted;;`
+	tags := ParseTagListOutput(output)
+	assert.Len(t, tags, 3)
+	assert.Equal(t, Tag{Name: "v0.1.0", ChangeId: "kmtnvwpx", Description: "release: 0.1.0"}, tags[0])
+	assert.Equal(t, "release: 0.2.0; with notes", tags[1].Description)
+	assert.False(t, tags[1].Conflict())
+	assert.True(t, tags[2].Conflict())
+}
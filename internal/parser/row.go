@@ -182,7 +182,7 @@ func (row *Row) Extend() GraphGutter {
 			case No:
 				extendMask[i] = false
 			case Carry:
-				extendMask[i] = extendMask[i]
+				// keeps the value of the previous line
 			}
 		}
 		lastGutter = &gl.Gutter
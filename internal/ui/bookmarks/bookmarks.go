@@ -26,6 +26,8 @@ type Model struct {
 	menu        menu.Menu
 	keymap      config.KeyMappings[key.Binding]
 	distanceMap map[string]int
+	width       int
+	height      int
 }
 
 func (m *Model) Width() int {
@@ -37,10 +39,12 @@ func (m *Model) Height() int {
 }
 
 func (m *Model) SetWidth(w int) {
+	m.width = w
 	m.menu.SetWidth(w)
 }
 
 func (m *Model) SetHeight(h int) {
+	m.height = h
 	m.menu.SetHeight(h)
 }
 
@@ -188,6 +192,15 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			action := m.menu.List.SelectedItem().(item)
 			return m, m.context.RunCommand(action.args, common.Refresh, common.Close)
+		case key.Matches(msg, m.keymap.Bookmark.Cleanup):
+			cleanup := NewCleanupModel(m.context, m.width, m.height)
+			return cleanup, cleanup.Init()
+		case key.Matches(msg, m.keymap.Bookmark.Browse):
+			browser := NewBrowserModel(m.context, m.width, m.height)
+			return browser, browser.Init()
+		case key.Matches(msg, m.keymap.Bookmark.Stack) && m.current != nil:
+			stack := NewStackModel(m.context, m.current.GetChangeId(), m.width, m.height)
+			return stack, stack.Init()
 		case key.Matches(msg, m.keymap.Bookmark.Move) && m.menu.Filter != "move":
 			return m.filtered("move")
 		case key.Matches(msg, m.keymap.Bookmark.Delete) && m.menu.Filter != "delete":
@@ -240,6 +253,9 @@ func (m *Model) View() string {
 		m.keymap.Bookmark.Forget,
 		m.keymap.Bookmark.Track,
 		m.keymap.Bookmark.Untrack,
+		m.keymap.Bookmark.Cleanup,
+		m.keymap.Bookmark.Browse,
+		m.keymap.Bookmark.Stack,
 	}
 
 	return m.menu.View(helpKeys)
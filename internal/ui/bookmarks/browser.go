@@ -0,0 +1,279 @@
+package bookmarks
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+)
+
+type updateBookmarkRefsMsg struct {
+	refs []jj.BookmarkRef
+}
+
+type sortOrder int
+
+const (
+	sortByName sortOrder = iota
+	sortByDate
+)
+
+func (s sortOrder) String() string {
+	if s == sortByDate {
+		return "date"
+	}
+	return "name"
+}
+
+// BrowserModel lists all local and remote bookmarks with their tracking state and
+// how far each local bookmark is ahead/behind of its tracked remote bookmarks
+type BrowserModel struct {
+	context   *context.MainContext
+	keymap    config.KeyMappings[key.Binding]
+	refs      []jj.BookmarkRef
+	visible   []jj.BookmarkRef
+	cursor    int
+	sort      sortOrder
+	filter    textinput.Model
+	filtering bool
+	loaded    bool
+	width     int
+	height    int
+	styles    common.DialogStyles
+}
+
+func (m *BrowserModel) Width() int {
+	return m.width
+}
+
+func (m *BrowserModel) Height() int {
+	return m.height
+}
+
+func (m *BrowserModel) SetWidth(w int) {
+	m.width = w
+}
+
+func (m *BrowserModel) SetHeight(h int) {
+	m.height = h
+}
+
+func (m *BrowserModel) ShortHelp() []key.Binding {
+	return []key.Binding{
+		m.keymap.Apply,
+		m.keymap.BookmarkBrowser.Revset,
+		m.keymap.BookmarkBrowser.Sort,
+		m.keymap.BookmarkBrowser.Filter,
+		m.keymap.Cancel,
+	}
+}
+
+func (m *BrowserModel) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+func (m *BrowserModel) Init() tea.Cmd {
+	return m.load
+}
+
+func (m *BrowserModel) load() tea.Msg {
+	output, _ := m.context.RunCommandImmediate(jj.BookmarkListRefs())
+	return updateBookmarkRefsMsg{refs: jj.ParseBookmarkRefListOutput(string(output))}
+}
+
+// apply sorts and filters the bookmarks into the visible list
+func (m *BrowserModel) apply() {
+	m.visible = m.visible[:0]
+	filter := strings.TrimSpace(m.filter.Value())
+	for _, ref := range m.refs {
+		if filter == "" || strings.Contains(ref.Symbol(), filter) {
+			m.visible = append(m.visible, ref)
+		}
+	}
+	slices.SortStableFunc(m.visible, func(a jj.BookmarkRef, b jj.BookmarkRef) int {
+		if m.sort == sortByDate {
+			if c := cmp.Compare(b.Timestamp, a.Timestamp); c != 0 {
+				return c
+			}
+		}
+		// keeps the remote bookmarks right after their local bookmark
+		return cmp.Or(strings.Compare(a.Name, b.Name), strings.Compare(a.Remote, b.Remote))
+	})
+	m.cursor = min(m.cursor, max(len(m.visible)-1, 0))
+}
+
+func (m *BrowserModel) selected() *jj.BookmarkRef {
+	if m.cursor < len(m.visible) {
+		return &m.visible[m.cursor]
+	}
+	return nil
+}
+
+// status describes the tracking state of a bookmark; local bookmarks are compared
+// against each of the remote bookmarks tracking them
+func (m *BrowserModel) status(ref jj.BookmarkRef) string {
+	if ref.Conflict {
+		return "conflicted"
+	}
+	if !ref.IsLocal() {
+		if ref.Tracked {
+			return "tracked"
+		}
+		return "untracked"
+	}
+	if !ref.Present {
+		return "deleted"
+	}
+	var states []string
+	for _, remote := range m.refs {
+		if remote.Name != ref.Name || remote.IsLocal() || !remote.Tracked {
+			continue
+		}
+		if remote.Ahead == 0 && remote.Behind == 0 {
+			states = append(states, fmt.Sprintf("%s in sync", remote.Remote))
+		} else {
+			states = append(states, fmt.Sprintf("%s ↑%d ↓%d", remote.Remote, remote.Ahead, remote.Behind))
+		}
+	}
+	if len(states) == 0 {
+		return "local only"
+	}
+	return strings.Join(states, ", ")
+}
+
+func (m *BrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(updateBookmarkRefsMsg); ok {
+		m.loaded = true
+		m.refs = msg.refs
+		m.apply()
+		return m, nil
+	}
+	if m.filtering {
+		return m.updateFilter(msg)
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keymap.Cancel):
+			if m.filter.Value() != "" {
+				m.filter.SetValue("")
+				m.apply()
+				return m, nil
+			}
+			return m, common.Close
+		case key.Matches(msg, m.keymap.Up):
+			m.cursor = max(m.cursor-1, 0)
+		case key.Matches(msg, m.keymap.Down):
+			m.cursor = min(m.cursor+1, max(len(m.visible)-1, 0))
+		case key.Matches(msg, m.keymap.Apply):
+			if ref := m.selected(); ref != nil && ref.ChangeId != "" {
+				return m, tea.Batch(common.Close, common.RefreshAndSelect(ref.ChangeId))
+			}
+		case key.Matches(msg, m.keymap.BookmarkBrowser.Revset):
+			if ref := m.selected(); ref != nil && ref.Present {
+				return m, tea.Batch(common.Close, common.UpdateRevSet(ref.Symbol()))
+			}
+		case key.Matches(msg, m.keymap.BookmarkBrowser.Sort):
+			m.sort = (m.sort + 1) % 2
+			m.apply()
+		case key.Matches(msg, m.keymap.BookmarkBrowser.Filter):
+			m.filtering = true
+			return m, m.filter.Focus()
+		}
+	}
+	return m, nil
+}
+
+func (m *BrowserModel) updateFilter(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keymap.Cancel):
+			m.filtering = false
+			m.filter.Blur()
+			m.filter.SetValue("")
+			m.apply()
+			return m, nil
+		case key.Matches(msg, m.keymap.Apply):
+			m.filtering = false
+			m.filter.Blur()
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.filter, cmd = m.filter.Update(msg)
+	m.apply()
+	return m, cmd
+}
+
+func (m *BrowserModel) View() string {
+	width := max(m.width-m.styles.Border.GetHorizontalFrameSize(), 40)
+	title := fmt.Sprintf("Bookmarks (%d, sorted by %s)", len(m.visible), m.sort)
+	lines := []string{m.styles.Title.Render(title), ""}
+
+	switch {
+	case !m.loaded:
+		lines = append(lines, m.styles.Dimmed.Render("loading..."))
+	case len(m.visible) == 0:
+		lines = append(lines, m.styles.Dimmed.Render("There are no bookmarks"))
+	default:
+		nameWidth, idWidth, ageWidth := 0, 0, 0
+		for _, ref := range m.visible {
+			nameWidth = max(nameWidth, lipgloss.Width(ref.Symbol()))
+			idWidth = max(idWidth, lipgloss.Width(ref.ChangeId))
+			ageWidth = max(ageWidth, lipgloss.Width(ref.Age))
+		}
+		// title, filter and help lines with the blank lines around them
+		const chrome = 6
+		listHeight := max(m.height-m.styles.Border.GetVerticalFrameSize()-chrome, 1)
+		start := max(m.cursor-listHeight+1, 0)
+		end := min(start+listHeight, len(m.visible))
+		for i := start; i < end; i++ {
+			ref := m.visible[i]
+			style := m.styles.Text
+			if i == m.cursor {
+				style = m.styles.Selected
+			}
+			line := fmt.Sprintf("%-*s  %-*s  %-*s  %s", nameWidth, ref.Symbol(), idWidth, ref.ChangeId, ageWidth, ref.Age, m.status(ref))
+			lines = append(lines, style.Width(width).MaxWidth(width).Render(line))
+		}
+	}
+
+	if m.filtering || m.filter.Value() != "" {
+		lines = append(lines, "", m.filter.View())
+	} else {
+		lines = append(lines, "", "")
+	}
+	lines = append(lines, "", m.styles.RenderHelp(m.ShortHelp()))
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	content = lipgloss.Place(width, lipgloss.Height(content), 0, 0, content, lipgloss.WithWhitespaceBackground(m.styles.Text.GetBackground()))
+	return m.styles.Border.Render(content)
+}
+
+func NewBrowserModel(c *context.MainContext, width int, height int) *BrowserModel {
+	s := common.NewDialogStyles("bookmarks")
+
+	filter := textinput.New()
+	filter.Prompt = "filter: "
+	filter.CharLimit = 120
+	filter.PromptStyle = s.Dimmed
+	filter.TextStyle = s.Text
+	filter.Cursor.TextStyle = s.Text
+
+	return &BrowserModel{
+		context: c,
+		keymap:  config.Current.GetKeyMap(),
+		filter:  filter,
+		width:   width,
+		height:  height,
+		styles:  s,
+	}
+}
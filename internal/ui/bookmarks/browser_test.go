@@ -0,0 +1,70 @@
+package bookmarks
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const refsOutput = `feature;.;false;false;true;kmtnvwpx;0;0;1700000000;2 days ago
+feature;origin;true;false;true;zqrsxlyo;1;3;1690000000;4 months ago
+main;.;false;false;true;rlvkpnrz;0;0;1710000000;1 hour ago
+main;origin;true;false;true;rlvkpnrz;0;0;1710000000;1 hour ago`
+
+func Test_Browser_ShowsAheadBehind(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.BookmarkListRefs()).SetOutput([]byte(refsOutput))
+	defer commandRunner.Verify()
+
+	model := NewBrowserModel(test.NewTestContext(commandRunner), 100, 20)
+	tm := teatest.NewTestModel(t, test.NewShell(model))
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("origin ↑3 ↓1")) && bytes.Contains(bts, []byte("origin in sync"))
+	})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
+func Test_Browser_SortAndFilter(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.BookmarkListRefs()).SetOutput([]byte(refsOutput))
+	defer commandRunner.Verify()
+
+	model := NewBrowserModel(test.NewTestContext(commandRunner), 100, 20)
+	tm := teatest.NewTestModel(t, test.NewShell(model))
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("sorted by name"))
+	})
+	tm.Type("s")
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("sorted by date"))
+	})
+	tm.Type("/")
+	tm.Type("main")
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("Bookmarks (2, sorted by date)"))
+	})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
+func Test_Browser_Select(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.BookmarkListRefs()).SetOutput([]byte(refsOutput))
+	defer commandRunner.Verify()
+
+	model := NewBrowserModel(test.NewTestContext(commandRunner), 100, 20)
+	model.Update(model.load())
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.NotNil(t, cmd)
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Nil(t, cmd)
+	assert.Equal(t, "feature@origin", model.selected().Symbol())
+}
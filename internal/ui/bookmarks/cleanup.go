@@ -0,0 +1,308 @@
+package bookmarks
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/confirmation"
+	"github.com/idursun/jjui/internal/ui/context"
+)
+
+type updateStaleBookmarksMsg struct {
+	bookmarks []jj.StaleBookmark
+	remotes   []string
+}
+
+type fetchFailedMsg struct {
+	output string
+}
+
+// CleanupModel lists the bookmarks which are merged into trunk(), deleted on their remote or whose
+// remote is gone, and deletes or forgets the checked ones in a single command
+type CleanupModel struct {
+	context      *context.MainContext
+	keymap       config.KeyMappings[key.Binding]
+	bookmarks    []jj.StaleBookmark
+	remotes      []string
+	checked      map[string]bool
+	cursor       int
+	push         bool
+	loaded       bool
+	fetchFailed  string
+	confirmation tea.Model
+	width        int
+	height       int
+	styles       common.DialogStyles
+}
+
+func (m *CleanupModel) Width() int {
+	return m.width
+}
+
+func (m *CleanupModel) Height() int {
+	return m.height
+}
+
+func (m *CleanupModel) SetWidth(w int) {
+	m.width = w
+}
+
+func (m *CleanupModel) SetHeight(h int) {
+	m.height = h
+}
+
+func (m *CleanupModel) ShortHelp() []key.Binding {
+	return []key.Binding{
+		m.keymap.ToggleSelect,
+		m.keymap.BookmarkCleanup.ToggleAll,
+		m.keymap.BookmarkCleanup.Delete,
+		m.keymap.BookmarkCleanup.Forget,
+		m.keymap.BookmarkCleanup.Push,
+		m.keymap.Cancel,
+	}
+}
+
+func (m *CleanupModel) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+// Init fetches from the remotes first, the bookmarks deleted on a remote are only known after their
+// deletion is fetched
+func (m *CleanupModel) Init() tea.Cmd {
+	return tea.Sequence(m.fetch, m.load)
+}
+
+func (m *CleanupModel) fetch() tea.Msg {
+	if output, err := m.context.RunCommandImmediate(jj.GitFetch("--all-remotes")); err != nil {
+		return fetchFailedMsg{output: strings.TrimSpace(string(output))}
+	}
+	return nil
+}
+
+func (m *CleanupModel) load() tea.Msg {
+	var remotes []string
+	if output, err := m.context.RunCommandImmediate(jj.GitRemoteList()); err == nil {
+		for _, remote := range jj.ParseRemoteListOutput(string(output)) {
+			remotes = append(remotes, remote.Name)
+		}
+	}
+	output, _ := m.context.RunCommandImmediate(jj.BookmarkListStale())
+	return updateStaleBookmarksMsg{
+		bookmarks: jj.ParseStaleBookmarkListOutput(string(output), remotes),
+		remotes:   remotes,
+	}
+}
+
+func (m *CleanupModel) checkedBookmarks() []jj.StaleBookmark {
+	var checked []jj.StaleBookmark
+	for _, b := range m.bookmarks {
+		if m.checked[b.Name] {
+			checked = append(checked, b)
+		}
+	}
+	return checked
+}
+
+// pushRemotes returns the names of the checked bookmarks grouped by the existing remotes tracking them
+func (m *CleanupModel) pushRemotes(bookmarks []jj.StaleBookmark) map[string][]string {
+	byRemote := make(map[string][]string)
+	for _, b := range bookmarks {
+		for _, remote := range b.Remotes {
+			if slices.Contains(m.remotes, remote) {
+				byRemote[remote] = append(byRemote[remote], b.Name)
+			}
+		}
+	}
+	return byRemote
+}
+
+func (m *CleanupModel) pushDeletion(remote string, names []string) tea.Cmd {
+	flags := []string{"--remote", remote}
+	for _, name := range names {
+		flags = append(flags, "--bookmark", name)
+	}
+	args := jj.GitPush(flags...)
+	return func() tea.Msg {
+		output, err := m.context.RunCommandImmediate(args)
+		return common.CommandCompletedMsg{Output: string(output), Err: err}
+	}
+}
+
+func (m *CleanupModel) delete() tea.Cmd {
+	bookmarks := m.checkedBookmarks()
+	var names []string
+	for _, b := range bookmarks {
+		// bookmarks which only exist on a removed remote can only be forgotten
+		if b.HasLocal {
+			names = append(names, b.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	var continuations []tea.Cmd
+	message := fmt.Sprintf("Are you sure you want to delete %d bookmarks?", len(names))
+	if m.push {
+		byRemote := m.pushRemotes(bookmarks)
+		var remotes []string
+		for remote := range byRemote {
+			remotes = append(remotes, remote)
+		}
+		slices.Sort(remotes)
+		for _, remote := range remotes {
+			continuations = append(continuations, m.pushDeletion(remote, byRemote[remote]))
+		}
+		if len(remotes) > 0 {
+			message = fmt.Sprintf("Are you sure you want to delete %d bookmarks and push the deletion to %s?", len(names), strings.Join(remotes, ", "))
+		}
+	}
+	continuations = append(continuations, common.Refresh, confirmation.Close, m.load)
+	return m.confirm(message, m.context.RunCommand(jj.BookmarkDelete(names...), continuations...))
+}
+
+func (m *CleanupModel) forget() tea.Cmd {
+	var names []string
+	for _, b := range m.checkedBookmarks() {
+		names = append(names, b.Name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	message := fmt.Sprintf("Are you sure you want to forget %d bookmarks?", len(names))
+	return m.confirm(message, m.context.RunCommand(jj.BookmarkForget(names...), common.Refresh, confirmation.Close, m.load))
+}
+
+func (m *CleanupModel) confirm(message string, cmd tea.Cmd) tea.Cmd {
+	model := confirmation.New(
+		[]string{message},
+		confirmation.WithStylePrefix("bookmarks"),
+		confirmation.WithOption("Yes", cmd, key.NewBinding(key.WithKeys("y"))),
+		confirmation.WithOption("No", confirmation.Close, key.NewBinding(key.WithKeys("n", "esc"))),
+	)
+	m.confirmation = &model
+	return m.confirmation.Init()
+}
+
+func (m *CleanupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case confirmation.CloseMsg:
+		m.confirmation = nil
+		return m, nil
+	case fetchFailedMsg:
+		m.fetchFailed = msg.output
+		return m, nil
+	case updateStaleBookmarksMsg:
+		m.loaded = true
+		m.bookmarks = msg.bookmarks
+		m.remotes = msg.remotes
+		m.checked = make(map[string]bool)
+		m.cursor = min(m.cursor, max(len(m.bookmarks)-1, 0))
+		return m, nil
+	}
+	if m.confirmation != nil {
+		var cmd tea.Cmd
+		m.confirmation, cmd = m.confirmation.Update(msg)
+		return m, cmd
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keymap.Cancel):
+			return m, common.Close
+		case key.Matches(msg, m.keymap.Up):
+			m.cursor = max(m.cursor-1, 0)
+		case key.Matches(msg, m.keymap.Down):
+			m.cursor = min(m.cursor+1, max(len(m.bookmarks)-1, 0))
+		case key.Matches(msg, m.keymap.ToggleSelect):
+			if m.cursor < len(m.bookmarks) {
+				name := m.bookmarks[m.cursor].Name
+				m.checked[name] = !m.checked[name]
+				m.cursor = min(m.cursor+1, len(m.bookmarks)-1)
+			}
+		case key.Matches(msg, m.keymap.BookmarkCleanup.ToggleAll):
+			all := len(m.checkedBookmarks()) == len(m.bookmarks)
+			for _, b := range m.bookmarks {
+				m.checked[b.Name] = !all
+			}
+		case key.Matches(msg, m.keymap.BookmarkCleanup.Push):
+			m.push = !m.push
+		case key.Matches(msg, m.keymap.BookmarkCleanup.Delete):
+			return m, m.delete()
+		case key.Matches(msg, m.keymap.BookmarkCleanup.Forget):
+			return m, m.forget()
+		}
+	}
+	return m, nil
+}
+
+func (m *CleanupModel) View() string {
+	if m.confirmation != nil {
+		return m.confirmation.View()
+	}
+	width := max(m.width-m.styles.Border.GetHorizontalFrameSize(), 40)
+	title := fmt.Sprintf("Stale Bookmarks (%d checked)", len(m.checkedBookmarks()))
+	lines := []string{m.styles.Title.Render(title), ""}
+
+	switch {
+	case !m.loaded:
+		lines = append(lines, m.styles.Dimmed.Render("loading..."))
+	case len(m.bookmarks) == 0:
+		lines = append(lines, m.styles.Dimmed.Render("There are no stale bookmarks"))
+	default:
+		nameWidth, ageWidth := 0, 0
+		for _, b := range m.bookmarks {
+			nameWidth = max(nameWidth, lipgloss.Width(b.Name))
+			ageWidth = max(ageWidth, lipgloss.Width(b.Age))
+		}
+		// title, push state and help lines with the blank lines around them
+		const chrome = 6
+		listHeight := max(m.height-m.styles.Border.GetVerticalFrameSize()-chrome, 1)
+		start := max(m.cursor-listHeight+1, 0)
+		end := min(start+listHeight, len(m.bookmarks))
+		for i := start; i < end; i++ {
+			b := m.bookmarks[i]
+			mark := "[ ]"
+			if m.checked[b.Name] {
+				mark = "[x]"
+			}
+			style := m.styles.Text
+			if i == m.cursor {
+				style = m.styles.Selected
+			}
+			line := fmt.Sprintf("%s %-*s  %-*s  %s", mark, nameWidth, b.Name, ageWidth, b.Age, b.Reason)
+			lines = append(lines, style.Width(width).MaxWidth(width).Render(line))
+		}
+	}
+
+	if m.fetchFailed != "" {
+		lines = append(lines, "", m.styles.Dimmed.Width(width).Render("fetch failed, the deleted remote bookmarks may be missing: "+m.fetchFailed))
+	}
+	pushState := "off"
+	if m.push {
+		pushState = "on"
+	}
+	lines = append(lines, "", m.styles.Dimmed.Render("push deletion to remotes: ")+m.styles.Text.Render(pushState))
+	lines = append(lines, "", m.styles.RenderHelp(m.ShortHelp()))
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	content = lipgloss.Place(width, lipgloss.Height(content), 0, 0, content, lipgloss.WithWhitespaceBackground(m.styles.Text.GetBackground()))
+	return m.styles.Border.Render(content)
+}
+
+func NewCleanupModel(c *context.MainContext, width int, height int) *CleanupModel {
+	return &CleanupModel{
+		context: c,
+		keymap:  config.Current.GetKeyMap(),
+		checked: make(map[string]bool),
+		width:   width,
+		height:  height,
+		styles:  common.NewDialogStyles("bookmarks"),
+	}
+}
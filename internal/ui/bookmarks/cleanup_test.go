@@ -0,0 +1,81 @@
+package bookmarks
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/test"
+)
+
+const staleOutput = `feature;.;false;true;true;2 months ago
+feature;origin;true;true;true;2 months ago
+fix;.;false;true;true;3 months ago
+old;fork;true;true;false;1 year ago`
+
+func Test_Cleanup_DeleteAndPush(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitFetch("--all-remotes"))
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte("origin git@github.com:idursun/jjui.git"))
+	commandRunner.Expect(jj.BookmarkListStale()).SetOutput([]byte(staleOutput))
+	commandRunner.Expect(jj.BookmarkDelete("feature", "fix"))
+	commandRunner.Expect(jj.GitPush("--remote", "origin", "--bookmark", "feature"))
+	defer commandRunner.Verify()
+
+	model := NewCleanupModel(test.NewTestContext(commandRunner), 80, 20)
+	tm := teatest.NewTestModel(t, test.NewShell(model))
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("remote fork was removed"))
+	})
+	tm.Type("a")
+	tm.Type("p")
+	tm.Type("d")
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("push the deletion to origin"))
+	})
+	tm.Type("y")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
+func Test_Cleanup_ForgetChecked(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitFetch("--all-remotes"))
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte("origin git@github.com:idursun/jjui.git"))
+	commandRunner.Expect(jj.BookmarkListStale()).SetOutput([]byte(staleOutput))
+	commandRunner.Expect(jj.BookmarkForget("old"))
+	defer commandRunner.Verify()
+
+	model := NewCleanupModel(test.NewTestContext(commandRunner), 80, 20)
+	tm := teatest.NewTestModel(t, test.NewShell(model))
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("remote fork was removed"))
+	})
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	tm.Type("f")
+	tm.Type("y")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
+func Test_Cleanup_ListsBookmarksDeletedOnRemote(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitFetch("--all-remotes")).SetOutput([]byte("Error: could not connect")).SetError(errors.New("exit status 1"))
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte("origin git@github.com:idursun/jjui.git"))
+	commandRunner.Expect(jj.BookmarkListStale()).SetOutput([]byte("gone;.;false;true;false;5 days ago\ngone;origin;true;false;false;-"))
+	defer commandRunner.Verify()
+
+	model := NewCleanupModel(test.NewTestContext(commandRunner), 120, 20)
+	tm := teatest.NewTestModel(t, test.NewShell(model))
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("deleted on origin")) && bytes.Contains(bts, []byte("could not connect"))
+	})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
@@ -0,0 +1,260 @@
+package bookmarks
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/confirmation"
+	"github.com/idursun/jjui/internal/ui/context"
+)
+
+const maxSlugLength = 40
+
+type updateStackMsg struct {
+	commits []jj.StackCommit
+}
+
+type stackBookmarksSetMsg struct {
+	names []string
+}
+
+// stackBookmark is the bookmark proposed for a revision of the stack
+type stackBookmark struct {
+	commit jj.StackCommit
+	name   string
+}
+
+// exists reports whether the bookmark already points to the revision
+func (b stackBookmark) exists() bool {
+	return slices.Contains(b.commit.Bookmarks, b.name)
+}
+
+// slugify turns a description into a bookmark friendly name
+func slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
+	}
+	return strings.Trim(slug, "-")
+}
+
+// bookmarkName expands the name template for the revision, falling back to the change id
+// when the revision has no description
+func bookmarkName(template string, commit jj.StackCommit) string {
+	slug := slugify(commit.Description)
+	if slug == "" {
+		slug = commit.ChangeId
+	}
+	return strings.NewReplacer(
+		"{user}", commit.User(),
+		"{description_slug}", slug,
+		"{change_id}", commit.ChangeId,
+	).Replace(template)
+}
+
+// stackBookmarks proposes a unique bookmark name for each revision of the stack
+func stackBookmarks(template string, commits []jj.StackCommit) []stackBookmark {
+	var bookmarks []stackBookmark
+	used := make(map[string]int)
+	for _, commit := range commits {
+		name := bookmarkName(template, commit)
+		if used[name]++; used[name] > 1 {
+			name = fmt.Sprintf("%s-%d", name, used[name])
+		}
+		bookmarks = append(bookmarks, stackBookmark{commit: commit, name: name})
+	}
+	return bookmarks
+}
+
+// StackModel creates or updates a bookmark for every revision of the stack ending at
+// the selected revision and offers to push them all
+type StackModel struct {
+	context      *context.MainContext
+	keymap       config.KeyMappings[key.Binding]
+	revision     string
+	template     string
+	bookmarks    []stackBookmark
+	loaded       bool
+	confirmation tea.Model
+	width        int
+	height       int
+	styles       common.DialogStyles
+}
+
+func (m *StackModel) Width() int {
+	return m.width
+}
+
+func (m *StackModel) Height() int {
+	return m.height
+}
+
+func (m *StackModel) SetWidth(w int) {
+	m.width = w
+}
+
+func (m *StackModel) SetHeight(h int) {
+	m.height = h
+}
+
+func (m *StackModel) ShortHelp() []key.Binding {
+	return []key.Binding{
+		m.keymap.Apply,
+		m.keymap.Cancel,
+	}
+}
+
+func (m *StackModel) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+func (m *StackModel) Init() tea.Cmd {
+	return m.load
+}
+
+func (m *StackModel) load() tea.Msg {
+	output, _ := m.context.RunCommandImmediate(jj.Stack(m.revision))
+	return updateStackMsg{commits: jj.ParseStackOutput(string(output))}
+}
+
+// setBookmarks points each bookmark to its revision, skipping the ones which are already there
+func (m *StackModel) setBookmarks() tea.Cmd {
+	bookmarks := m.bookmarks
+	return tea.Sequence(
+		func() tea.Msg {
+			return common.CommandRunningMsg(fmt.Sprintf("setting %d bookmarks of the stack", len(bookmarks)))
+		},
+		m.set(bookmarks),
+		common.Refresh,
+	)
+}
+
+// set runs the bookmark commands one after another and stops at the first failure. The existing
+// bookmarks of the stack may be moved backwards when the stack is rebased onto an older trunk.
+func (m *StackModel) set(bookmarks []stackBookmark) tea.Cmd {
+	return func() tea.Msg {
+		var names []string
+		for _, b := range bookmarks {
+			names = append(names, b.name)
+			if b.exists() {
+				continue
+			}
+			if output, err := m.context.RunCommandImmediate(jj.BookmarkSet(b.commit.ChangeId, b.name, "--allow-backwards")); err != nil {
+				return common.CommandCompletedMsg{Output: string(output), Err: err}
+			}
+		}
+		return stackBookmarksSetMsg{names: names}
+	}
+}
+
+func (m *StackModel) confirmPush(names []string) tea.Cmd {
+	flags := []string{"--allow-new"}
+	for _, name := range names {
+		flags = append(flags, "--bookmark", name)
+	}
+	model := confirmation.New(
+		[]string{fmt.Sprintf("Bookmarks are set. Do you want to push %d bookmarks?", len(names))},
+		confirmation.WithStylePrefix("bookmarks"),
+		confirmation.WithOption("Yes", m.context.RunCommand(jj.GitPush(flags...), common.Refresh, common.Close), key.NewBinding(key.WithKeys("y"))),
+		confirmation.WithOption("No", common.Close, key.NewBinding(key.WithKeys("n", "esc"))),
+	)
+	m.confirmation = &model
+	return m.confirmation.Init()
+}
+
+func (m *StackModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case updateStackMsg:
+		m.loaded = true
+		m.bookmarks = stackBookmarks(m.template, msg.commits)
+		return m, nil
+	case stackBookmarksSetMsg:
+		return m, m.confirmPush(msg.names)
+	}
+	if m.confirmation != nil {
+		var cmd tea.Cmd
+		m.confirmation, cmd = m.confirmation.Update(msg)
+		return m, cmd
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keymap.Cancel):
+			return m, common.Close
+		case key.Matches(msg, m.keymap.Apply):
+			if len(m.bookmarks) > 0 {
+				return m, m.setBookmarks()
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *StackModel) View() string {
+	if m.confirmation != nil {
+		return m.confirmation.View()
+	}
+	width := max(m.width-m.styles.Border.GetHorizontalFrameSize(), 40)
+	lines := []string{m.styles.Title.Render(fmt.Sprintf("Bookmark the stack of %s", m.revision)), ""}
+
+	switch {
+	case !m.loaded:
+		lines = append(lines, m.styles.Dimmed.Render("loading..."))
+	case len(m.bookmarks) == 0:
+		lines = append(lines, m.styles.Dimmed.Render("There are no revisions between trunk() and "+m.revision))
+	default:
+		idWidth, nameWidth := 0, 0
+		for _, b := range m.bookmarks {
+			idWidth = max(idWidth, lipgloss.Width(b.commit.ChangeId))
+			nameWidth = max(nameWidth, lipgloss.Width(b.name))
+		}
+		// title and help lines with the blank lines around them
+		const chrome = 4
+		listHeight := max(m.height-m.styles.Border.GetVerticalFrameSize()-chrome, 1)
+		// the head of the stack is the most relevant part when it doesn't fit
+		start := max(len(m.bookmarks)-listHeight, 0)
+		for _, b := range m.bookmarks[start:] {
+			state := "set"
+			if b.exists() {
+				state = "exists"
+			}
+			line := fmt.Sprintf("%-*s  %-*s  %-6s  %s", idWidth, b.commit.ChangeId, nameWidth, b.name, state, b.commit.Description)
+			lines = append(lines, m.styles.Text.Width(width).MaxWidth(width).Render(line))
+		}
+	}
+
+	lines = append(lines, "", m.styles.RenderHelp(m.ShortHelp()))
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	content = lipgloss.Place(width, lipgloss.Height(content), 0, 0, content, lipgloss.WithWhitespaceBackground(m.styles.Text.GetBackground()))
+	return m.styles.Border.Render(content)
+}
+
+func NewStackModel(c *context.MainContext, revision string, width int, height int) *StackModel {
+	return &StackModel{
+		context:  c,
+		keymap:   config.Current.GetKeyMap(),
+		revision: revision,
+		template: config.Current.Bookmark.NameTemplate,
+		width:    width,
+		height:   height,
+		styles:   common.NewDialogStyles("bookmarks"),
+	}
+}
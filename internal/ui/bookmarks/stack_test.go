@@ -0,0 +1,72 @@
+package bookmarks
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const stackOutput = `kmtnvwpx;kmtnvwpxlzsruqqlnsxpmtywkquqqkuo;jane@example.com;;feat: Add the parser!
+zqrsxlyo;zqrsxlyoqmtlvnwkrnzqkqnrxoxkmlzl;jane@example.com;jane/fix-tests;fix tests
+rlvkpnrz;rlvkpnrzqnoowoytxnquwvuryrwnrmlp;jane@example.com;;`
+
+func Test_BookmarkName(t *testing.T) {
+	commit := jj.StackCommit{ChangeId: "kmtnvwpx", Email: "jane@example.com", Description: "feat(ui): Add the  parser!"}
+	assert.Equal(t, "jane/feat-ui-add-the-parser", bookmarkName("{user}/{description_slug}", commit))
+	assert.Equal(t, "push-kmtnvwpx", bookmarkName("push-{change_id}", commit))
+	commit.Description = ""
+	assert.Equal(t, "jane/kmtnvwpx", bookmarkName("{user}/{description_slug}", commit))
+}
+
+func Test_StackBookmarks_UniqueNames(t *testing.T) {
+	commits := []jj.StackCommit{
+		{ChangeId: "a", Email: "jane@example.com", Description: "wip"},
+		{ChangeId: "b", Email: "jane@example.com", Description: "wip"},
+	}
+	bookmarks := stackBookmarks("{user}/{description_slug}", commits)
+	assert.Equal(t, "jane/wip", bookmarks[0].name)
+	assert.Equal(t, "jane/wip-2", bookmarks[1].name)
+}
+
+func Test_Stack_SetAndPush(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Stack("rlvkpnrz")).SetOutput([]byte(stackOutput))
+	commandRunner.Expect(jj.BookmarkSet("kmtnvwpx", "jane/feat-add-the-parser", "--allow-backwards"))
+	commandRunner.Expect(jj.BookmarkSet("rlvkpnrz", "jane/rlvkpnrz", "--allow-backwards"))
+	commandRunner.Expect(jj.GitPush("--allow-new", "--bookmark", "jane/feat-add-the-parser", "--bookmark", "jane/fix-tests", "--bookmark", "jane/rlvkpnrz"))
+	defer commandRunner.Verify()
+
+	model := NewStackModel(test.NewTestContext(commandRunner), "rlvkpnrz", 100, 20)
+	model.template = "{user}/{description_slug}"
+	tm := teatest.NewTestModel(t, test.NewShell(model))
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("jane/fix-tests"))
+	})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("push 3 bookmarks"))
+	})
+	tm.Type("y")
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
+func Test_Stack_StopsAtFirstFailure(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.BookmarkSet("kmtnvwpx", "jane/feat-add-the-parser", "--allow-backwards")).SetError(errors.New("refusing to move bookmark"))
+	defer commandRunner.Verify()
+
+	model := NewStackModel(test.NewTestContext(commandRunner), "rlvkpnrz", 100, 20)
+	bookmarks := stackBookmarks("{user}/{description_slug}", jj.ParseStackOutput(stackOutput))
+	msg := model.set(bookmarks)()
+	completed, ok := msg.(common.CommandCompletedMsg)
+	assert.True(t, ok)
+	assert.Error(t, completed.Err)
+}
@@ -0,0 +1,310 @@
+package command_palette
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/fuzzy_search"
+	"github.com/idursun/jjui/internal/ui/keysequence"
+	"github.com/idursun/jjui/internal/ui/leader"
+	"github.com/sahilm/fuzzy"
+)
+
+type item struct {
+	name string
+	// detail is shown next to the name, it is the keys of the action or the expansion of the revset
+	detail string
+	cmd    tea.Cmd
+}
+
+type commandPalette struct {
+	keyMap  config.KeyMappings[key.Binding]
+	inputKm textinput.KeyMap
+	items   []item
+	width   int
+
+	cursor  int
+	max     int
+	matches fuzzy.Matches
+	styles  fuzzy_search.Styles
+}
+
+func (p *commandPalette) Init() tea.Cmd {
+	return fuzzy_search.Init()
+}
+
+func (p *commandPalette) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case fuzzy_search.InitMsg:
+		p.search("")
+	case fuzzy_search.SearchMsg:
+		if cmd := p.handleKey(msg.Pressed); cmd != nil {
+			return p, cmd
+		}
+		p.search(msg.Input)
+	case tea.KeyMsg:
+		return p, p.handleKey(msg)
+	}
+	return p, nil
+}
+
+func (p *commandPalette) handleKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, p.keyMap.FileSearch.Up, p.keyMap.Preview.ScrollUp):
+		p.cursor = fuzzy_search.MoveCursor(p.cursor, 1, p.matches)
+		return fuzzy_search.SkipSearch
+	case key.Matches(msg, p.keyMap.FileSearch.Down, p.keyMap.Preview.ScrollDown):
+		p.cursor = fuzzy_search.MoveCursor(p.cursor, -1, p.matches)
+		return fuzzy_search.SkipSearch
+	case key.Matches(msg, p.keyMap.Apply):
+		if p.cursor < len(p.matches) {
+			return p.items[p.matches[p.cursor].Index].cmd
+		}
+		return fuzzy_search.SkipSearch
+	case fuzzy_search.IsInputMovement(p.inputKm, msg):
+		return fuzzy_search.SkipSearch
+	}
+	return nil
+}
+
+func (p *commandPalette) Styles() fuzzy_search.Styles {
+	return p.styles
+}
+
+func (p *commandPalette) Max() int {
+	return p.max
+}
+
+func (p *commandPalette) Matches() fuzzy.Matches {
+	return p.matches
+}
+
+func (p *commandPalette) SelectedMatch() int {
+	return p.cursor
+}
+
+func (p *commandPalette) Len() int {
+	return len(p.items)
+}
+
+func (p *commandPalette) String(i int) string {
+	if i < 0 || i >= len(p.items) {
+		return ""
+	}
+	return fmt.Sprintf("%-*s  %s", p.width, p.items[i].name, p.items[i].detail)
+}
+
+func (p *commandPalette) search(input string) {
+	src := &fuzzy_search.RefinedSource{Source: p}
+	p.cursor = 0
+	p.matches = src.Search(input, p.max)
+}
+
+func (p *commandPalette) View() string {
+	title := p.styles.SelectedMatch.Render(
+		"  ",
+		strconv.Itoa(len(p.matches)),
+		"of",
+		strconv.Itoa(len(p.items)),
+		"commands",
+		" ",
+	)
+	entries := fuzzy_search.View(p)
+	return lipgloss.JoinVertical(0, title, entries)
+}
+
+func (p *commandPalette) ShortHelp() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys(p.keyMap.Apply.Keys()...), key.WithHelp(p.keyMap.Apply.Help().Key, "run")),
+	}
+}
+
+func (p *commandPalette) FullHelp() [][]key.Binding {
+	return [][]key.Binding{p.ShortHelp()}
+}
+
+type editStatus func() (help.KeyMap, string)
+
+func (p *commandPalette) editStatus() (help.KeyMap, string) {
+	return p, ""
+}
+
+// NewModel lists the actions which can be run on the selected item: the actions of the revisions,
+// the custom commands and the leader actions with the keys they are bound to, and the revset aliases
+func NewModel(ctx *context.MainContext) (fuzzy_search.Model, editStatus) {
+	keyMap := config.Current.GetKeyMap()
+	p := &commandPalette{
+		keyMap:  keyMap,
+		inputKm: textinput.DefaultKeyMap,
+		max:     30,
+		styles:  fuzzy_search.NewStyles(),
+	}
+
+	_, onRevision := ctx.SelectedItem.(context.SelectedRevision)
+	for _, action := range actions(keyMap) {
+		if action.revision && !onRevision {
+			continue
+		}
+		p.items = append(p.items, action.item())
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(ctx.CustomCommands)) {
+		command := ctx.CustomCommands[name]
+		if !command.IsApplicableTo(ctx.SelectedItem) {
+			continue
+		}
+		p.items = append(p.items, item{
+			name:   name,
+			detail: config.JoinKeys(command.Binding().Keys()),
+			cmd:    command.Prepare(ctx),
+		})
+	}
+
+	if leaderKeys := keyMap.Leader.Keys(); len(leaderKeys) > 0 {
+		for _, entry := range leader.Entries(ctx) {
+			p.items = append(p.items, item{
+				name:   entry.Help,
+				detail: config.JoinKeys(leaderKeys[:1]) + " " + strings.Join(entry.Keys, " "),
+				// the leader entries send their keys once the leader is closed
+				cmd: entry.Cmd,
+			})
+		}
+	}
+
+	if ctx.JJConfig != nil {
+		for _, alias := range slices.Sorted(maps.Keys(ctx.JJConfig.RevsetAliases)) {
+			// the aliases with parameters can't be used on their own
+			if strings.Contains(alias, "(") {
+				continue
+			}
+			p.items = append(p.items, item{
+				name:   "revset " + alias,
+				detail: ctx.JJConfig.RevsetAliases[alias],
+				cmd:    common.UpdateRevSet(alias),
+			})
+		}
+	}
+	if ctx.DefaultRevset != "" {
+		p.items = append(p.items, item{name: "revset default", detail: ctx.DefaultRevset, cmd: common.UpdateRevSet(ctx.DefaultRevset)})
+	}
+
+	for _, item := range p.items {
+		p.width = max(p.width, len(item.name))
+	}
+	return p, p.editStatus
+}
+
+// skipped are the actions which can't run on their own, they move in a list or complete what is
+// started in the mode
+var skipped = []string{
+	"up", "down", "apply", "cancel", "command_palette",
+	"file_search.up", "file_search.down", "file_search.accept", "file_search.edit",
+	"inline_describe.accept", "details.close", "details.select",
+	"metaedit.next", "metaedit.prev", "metaedit.toggle",
+	"divergence.next", "divergence.prev",
+}
+
+// global are the actions and the modes which don't need a selected revision
+var global = []string{
+	"jump_to_working_copy", "jump_to_top", "jump_to_bottom", "half_page_down", "half_page_up",
+	"refresh", "quit", "help", "undo", "revset", "exec_jj", "exec_shell", "quick_search",
+	"quick_search_cycle", "custom_commands", "macro_record", "macro_replay", "leader", "suspend",
+	"cancel_command", "git", "oplog", "divergence", "bookmark_browser", "command_log", "preview",
+}
+
+// direct are the modes whose actions are bound in the revisions, they run without opening the mode
+var direct = []string{"preview", "file_search"}
+
+type action struct {
+	// mode is the binding which opens the mode of the action, it is empty for the actions of the
+	// revisions
+	mode    key.Binding
+	binding key.Binding
+	// revision is set for the actions which need a selected revision
+	revision bool
+}
+
+func (a action) item() item {
+	name, detail, cmd := a.binding.Help().Desc, a.binding.Help().Key, send(a.binding.Keys()[0])
+	if a.mode.Enabled() {
+		if mode := a.mode.Help().Desc; !strings.HasPrefix(name, mode) {
+			name = mode + " " + name
+		}
+		detail = a.mode.Help().Key + " " + detail
+		mode, action := keyMsg(a.mode.Keys()[0]), keyMsg(a.binding.Keys()[0])
+		cmd = func() tea.Msg {
+			return common.ModeActionMsg{Mode: mode, Action: action}
+		}
+	}
+	return item{name: name, detail: detail, cmd: cmd}
+}
+
+// actions returns the actions of the key map in the order they are declared, the actions of a mode
+// follow the binding which opens the mode
+func actions(km config.KeyMappings[key.Binding]) []action {
+	var actions []action
+	add := func(name string, mode key.Binding, binding key.Binding, revision bool) {
+		if binding.Enabled() && !slices.Contains(skipped, name) {
+			actions = append(actions, action{mode: mode, binding: binding, revision: revision})
+		}
+	}
+	v := reflect.ValueOf(km)
+	for i := range v.NumField() {
+		field, name := v.Field(i), v.Type().Field(i).Tag.Get("toml")
+		revision := !slices.Contains(global, name)
+		if binding, ok := field.Interface().(key.Binding); ok {
+			add(name, key.Binding{}, binding, revision)
+			continue
+		}
+		if field.Kind() != reflect.Struct {
+			continue
+		}
+		var mode key.Binding
+		if !slices.Contains(direct, name) {
+			modeField := field.FieldByName("Mode")
+			if !modeField.IsValid() {
+				// the actions of the modes opened from other modes, e.g. the remotes of git
+				continue
+			}
+			mode = modeField.Interface().(key.Binding)
+		}
+		for j := range field.NumField() {
+			action := field.Type().Field(j).Tag.Get("toml")
+			binding := field.Field(j).Interface().(key.Binding)
+			if action == "mode" {
+				add(name, key.Binding{}, binding, revision)
+				continue
+			}
+			add(name+"."+action, mode, binding, revision)
+		}
+	}
+	return actions
+}
+
+// send presses the key once the palette is closed
+func send(k string) tea.Cmd {
+	msg := keyMsg(k)
+	return func() tea.Msg {
+		return keysequence.KeyMsg(msg)
+	}
+}
+
+// keyMsg returns the message of the key, a key sequence is sent as it is resolved
+func keyMsg(k string) tea.KeyMsg {
+	if config.SplitKeySequence(k) != nil {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+	}
+	return leader.Key(k)
+}
@@ -0,0 +1,138 @@
+package command_palette
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/keysequence"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type runMsg struct {
+	cmd tea.Cmd
+}
+
+// recorder runs the cmd of an item and records the messages it sends
+type recorder struct {
+	msgs []tea.Msg
+}
+
+func (r *recorder) Init() tea.Cmd {
+	return nil
+}
+
+func (r *recorder) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(runMsg); ok {
+		return r, msg.cmd
+	}
+	r.msgs = append(r.msgs, msg)
+	return r, nil
+}
+
+func (r *recorder) View() string {
+	return ""
+}
+
+func newPalette(t *testing.T, selected context.SelectedItem, customCommands string) *commandPalette {
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	ctx.SelectedItem = selected
+	ctx.JJConfig = &config.JJConfig{RevsetAliases: map[string]string{
+		"mine":       "author(me)",
+		"stack(x)":   "x::",
+		"unfinished": "mutable() & empty()",
+	}}
+	if customCommands != "" {
+		commands, err := context.LoadCustomCommands(customCommands)
+		require.NoError(t, err)
+		ctx.CustomCommands = commands
+	}
+	model, _ := NewModel(ctx)
+	return model.(*commandPalette)
+}
+
+func find(t *testing.T, p *commandPalette, name string) item {
+	t.Helper()
+	idx := slices.IndexFunc(p.items, func(i item) bool { return i.name == name })
+	require.NotEqual(t, -1, idx, "%s is not in the palette", name)
+	return p.items[idx]
+}
+
+func has(p *commandPalette, name string) bool {
+	return slices.ContainsFunc(p.items, func(i item) bool { return i.name == name })
+}
+
+func run(t *testing.T, cmd tea.Cmd) []tea.Msg {
+	r := &recorder{}
+	h := test.NewHarness(t, r)
+	// the window size is sent when the harness starts
+	r.msgs = nil
+	h.Send(runMsg{cmd: cmd})
+	return r.msgs
+}
+
+func TestActions_FollowTheKeyMap(t *testing.T) {
+	var names []string
+	for _, action := range actions(config.Current.GetKeyMap()) {
+		names = append(names, action.item().name)
+	}
+	assert.Contains(t, names, "rebase")
+	assert.Contains(t, names, "rebase insert after")
+	assert.Contains(t, names, "git push")
+	assert.Contains(t, names, "preview scroll down")
+	assert.Contains(t, names, "fuzzy files search")
+	assert.NotContains(t, names, "up")
+	assert.NotContains(t, names, "apply")
+	assert.NotContains(t, names, "command palette")
+	assert.NotContains(t, names, "metaedit next field")
+	// the remotes are opened from the git menu, they have no mode of their own
+	assert.NotContains(t, names, "rename")
+}
+
+func TestNewModel_OnRevision(t *testing.T) {
+	p := newPalette(t, context.SelectedRevision{ChangeId: "nyqzpsmt"}, `
+[custom_commands]
+"show file" = { key = ["ctrl+f"], args = ["file", "show", "$file"] }
+"mine" = { key = ["M"], revset = "mine() & ::$change_id" }
+`)
+	assert.True(t, has(p, "new"))
+	assert.True(t, has(p, "quit"))
+	assert.True(t, has(p, "mine"))
+	assert.False(t, has(p, "show file"))
+	assert.True(t, has(p, "revset mine"))
+	assert.False(t, has(p, "revset stack(x)"))
+}
+
+func TestNewModel_WithoutRevision(t *testing.T) {
+	p := newPalette(t, nil, `
+[custom_commands]
+"mine" = { key = ["M"], revset = "mine()" }
+"fetch all" = { key = ["F"], args = ["git", "fetch", "--all-remotes"] }
+`)
+	assert.False(t, has(p, "new"))
+	assert.False(t, has(p, "rebase insert after"))
+	assert.False(t, has(p, "mine"))
+	assert.True(t, has(p, "quit"))
+	assert.True(t, has(p, "git push"))
+	assert.True(t, has(p, "fetch all"))
+	assert.True(t, has(p, "revset unfinished"))
+}
+
+func TestNewModel_Cmds(t *testing.T) {
+	p := newPalette(t, context.SelectedRevision{ChangeId: "nyqzpsmt"}, `
+[custom_commands]
+"describe empty" = { key = ["ctrl+y"], macro = ["D", "ctrl+s"] }
+`)
+
+	assert.Equal(t, []tea.Msg{keysequence.KeyMsg(test.Key("n"))}, run(t, find(t, p, "new").cmd))
+	assert.Equal(t, []tea.Msg{common.ModeActionMsg{Mode: test.Key("r"), Action: test.Key("a")}},
+		run(t, find(t, p, "rebase insert after").cmd))
+	assert.Equal(t, []tea.Msg{keysequence.KeyMsg(test.Key("ctrl+n"))}, run(t, find(t, p, "preview scroll down").cmd))
+	assert.Equal(t, []tea.Msg{common.PlayMacroMsg{Keys: []string{"D", "ctrl+s"}}}, run(t, find(t, p, "describe empty").cmd))
+	assert.Equal(t, []tea.Msg{common.UpdateRevSetMsg("mine")}, run(t, find(t, p, "revset mine").cmd))
+}
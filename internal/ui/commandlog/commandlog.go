@@ -0,0 +1,231 @@
+package commandlog
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/muesli/termenv"
+)
+
+// copyToClipboard copies the text to the clipboard of the terminal
+var copyToClipboard = termenv.Copy
+
+// Model lists the commands run during the session, newest first, with the details and
+// the output of the selected one
+type Model struct {
+	context *context.MainContext
+	keyMap  config.KeyMappings[key.Binding]
+	entries []context.CommandLogEntry
+	showAll bool
+	cursor  int
+	note    string
+	width   int
+	height  int
+	styles  common.DialogStyles
+}
+
+func (m *Model) Width() int {
+	return m.width
+}
+
+func (m *Model) Height() int {
+	return m.height
+}
+
+func (m *Model) SetWidth(w int) {
+	m.width = w
+}
+
+func (m *Model) SetHeight(h int) {
+	m.height = h
+}
+
+func (m *Model) ShortHelp() []key.Binding {
+	return []key.Binding{
+		m.keyMap.CommandLog.Rerun,
+		m.keyMap.CommandLog.Copy,
+		m.keyMap.CommandLog.Output,
+		m.keyMap.CommandLog.All,
+		m.keyMap.Cancel,
+	}
+}
+
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+func (m *Model) Init() tea.Cmd {
+	m.load()
+	return nil
+}
+
+// load takes the entries from the command log; commands run in the background to load
+// data are only listed when all commands are shown
+func (m *Model) load() {
+	m.entries = nil
+	for _, entry := range m.context.CommandLog.Entries() {
+		if entry.Immediate && !m.showAll {
+			continue
+		}
+		m.entries = append(m.entries, entry)
+	}
+	slices.Reverse(m.entries)
+	m.cursor = min(m.cursor, max(len(m.entries)-1, 0))
+}
+
+func (m *Model) selected() *context.CommandLogEntry {
+	if m.cursor < len(m.entries) {
+		return &m.entries[m.cursor]
+	}
+	return nil
+}
+
+func (m *Model) rerun(entry context.CommandLogEntry) tea.Cmd {
+	switch {
+	case entry.IsShell():
+		return func() tea.Msg {
+			return common.ExecMsg{Line: entry.String(), Mode: common.ExecShell}
+		}
+	case entry.Interactive:
+		return m.context.RunInteractiveCommand(entry.Args, common.Refresh)
+	default:
+		return m.context.RunCommand(entry.Args, common.Refresh)
+	}
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case common.CommandCompletedMsg:
+		m.load()
+	case tea.KeyMsg:
+		m.note = ""
+		switch {
+		case key.Matches(msg, m.keyMap.Cancel):
+			return m, common.Close
+		case key.Matches(msg, m.keyMap.Up):
+			m.cursor = max(m.cursor-1, 0)
+		case key.Matches(msg, m.keyMap.Down):
+			m.cursor = min(m.cursor+1, max(len(m.entries)-1, 0))
+		case key.Matches(msg, m.keyMap.CommandLog.All):
+			m.showAll = !m.showAll
+			m.cursor = 0
+			m.load()
+		case key.Matches(msg, m.keyMap.CommandLog.Rerun):
+			if entry := m.selected(); entry != nil {
+				return m, m.rerun(*entry)
+			}
+		case key.Matches(msg, m.keyMap.CommandLog.Copy):
+			if entry := m.selected(); entry != nil {
+				copyToClipboard(entry.String())
+				m.note = "copied to clipboard"
+			}
+		case key.Matches(msg, m.keyMap.CommandLog.Output):
+			if entry := m.selected(); entry != nil {
+				return m, func() tea.Msg {
+					return common.ShowDiffMsg(fullOutput(*entry))
+				}
+			}
+		}
+	}
+	return m, nil
+}
+
+func fullOutput(entry context.CommandLogEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "$ %s\n", entry.String())
+	fmt.Fprintf(&b, "exit status %d, took %s", entry.ExitCode, entry.Duration.Round(time.Millisecond))
+	if entry.OperationId != "" {
+		fmt.Fprintf(&b, ", created operation %s", entry.OperationId)
+	}
+	b.WriteString("\n")
+	if entry.Stdout != "" {
+		b.WriteString("\nstdout:\n" + entry.Stdout + "\n")
+	}
+	if entry.Stderr != "" {
+		b.WriteString("\nstderr:\n" + entry.Stderr + "\n")
+	}
+	return b.String()
+}
+
+func (m *Model) marker(entry context.CommandLogEntry) string {
+	if entry.ExitCode != 0 {
+		return m.styles.Error.Render("✗")
+	}
+	return m.styles.Success.Render("✓")
+}
+
+func (m *Model) View() string {
+	width := max(m.width-m.styles.Border.GetHorizontalFrameSize(), 40)
+	height := max(m.height-m.styles.Border.GetVerticalFrameSize(), 10)
+	title := fmt.Sprintf("Command Log (%d commands)", len(m.entries))
+	if m.showAll {
+		title = fmt.Sprintf("Command Log (%d commands, including background commands)", len(m.entries))
+	}
+	lines := []string{m.styles.Title.Render(title), ""}
+
+	// title, help and the blank lines around them
+	const chrome = 5
+	listHeight := max((height-chrome)/2, 1)
+	if len(m.entries) == 0 {
+		lines = append(lines, m.styles.Dimmed.Render("No commands have been run yet"))
+	} else {
+		start := max(m.cursor-listHeight+1, 0)
+		end := min(start+listHeight, len(m.entries))
+		for i := start; i < end; i++ {
+			entry := m.entries[i]
+			style := m.styles.Text
+			if i == m.cursor {
+				style = m.styles.Selected
+			}
+			line := fmt.Sprintf("%s %s %8s  %s", m.marker(entry), entry.Start.Format("15:04:05"), entry.Duration.Round(time.Millisecond), entry.String())
+			lines = append(lines, style.Width(width).MaxWidth(width).Height(1).MaxHeight(1).Render(line))
+		}
+	}
+	lines = append(lines, "")
+
+	if entry := m.selected(); entry != nil {
+		details := fmt.Sprintf("exit status %d", entry.ExitCode)
+		if entry.OperationId != "" {
+			details += " • operation " + entry.OperationId
+		}
+		if m.note != "" {
+			details += " • " + m.note
+		}
+		lines = append(lines, m.styles.Dimmed.Render(details))
+		output := strings.TrimSpace(entry.Stdout + "\n" + entry.Stderr)
+		if output == "" {
+			output = "(no captured output)"
+		}
+		outputHeight := max(height-chrome-listHeight-1, 1)
+		outputLines := strings.Split(output, "\n")
+		if len(outputLines) > outputHeight {
+			outputLines = append(outputLines[:outputHeight-1], m.styles.Dimmed.Render(fmt.Sprintf("... %d more lines", len(outputLines)-outputHeight+1)))
+		}
+		for _, line := range outputLines {
+			lines = append(lines, m.styles.Text.Width(width).MaxWidth(width).Height(1).MaxHeight(1).Render(line))
+		}
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	content = lipgloss.Place(width, height-2, 0, 0, content, lipgloss.WithWhitespaceBackground(m.styles.Text.GetBackground()))
+	content = lipgloss.JoinVertical(lipgloss.Left, content, "", m.styles.RenderHelp(m.ShortHelp()))
+	return m.styles.Border.Render(content)
+}
+
+func NewModel(c *context.MainContext, width int, height int) *Model {
+	return &Model{
+		context: c,
+		keyMap:  config.Current.GetKeyMap(),
+		width:   width,
+		height:  height,
+		styles:  common.NewDialogStyles("command_log"),
+	}
+}
@@ -0,0 +1,86 @@
+package commandlog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCommandLog() *context.CommandLog {
+	log := context.NewCommandLog()
+	log.Add(context.CommandLogEntry{Program: "jj", Args: []string{"log", "-r", "@"}, Immediate: true, Stdout: "loading"})
+	log.Add(context.CommandLogEntry{Program: "jj", Args: []string{"new", "-r", "abc"}, Duration: 120 * time.Millisecond, Stderr: "Working copy now at: xyz", OperationId: "f0e1d2c3"})
+	log.Add(context.CommandLogEntry{Program: "jj", Args: []string{"abandon", "-r", "def"}, ExitCode: 1, Stderr: "Error: Revision def doesn't exist"})
+	return log
+}
+
+func Test_ShowsDetailsOfTheLastCommand(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CommandLog = newCommandLog()
+	model := NewModel(ctx, 100, 20)
+	model.Init()
+
+	assert.Len(t, model.entries, 2)
+	view := model.View()
+	assert.Contains(t, view, "jj abandon -r def")
+	assert.Contains(t, view, "jj new -r abc")
+	assert.NotContains(t, view, "jj log -r @")
+	assert.Contains(t, view, "exit status 1")
+	assert.Contains(t, view, "doesn't exist")
+}
+
+func Test_ShowAllIncludesBackgroundCommands(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CommandLog = newCommandLog()
+	model := NewModel(ctx, 100, 20)
+	tm := teatest.NewTestModel(t, test.NewShell(model))
+	tm.Type("a")
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("jj log -r @"))
+	})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
+func Test_Rerun(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect([]string{"new", "-r", "abc"})
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CommandLog = newCommandLog()
+	model := NewModel(ctx, 100, 20)
+	tm := teatest.NewTestModel(t, test.NewShell(model))
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Type("r")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
+func Test_Copy(t *testing.T) {
+	var copied string
+	original := copyToClipboard
+	copyToClipboard = func(s string) { copied = s }
+	defer func() { copyToClipboard = original }()
+
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	ctx.CommandLog = newCommandLog()
+	model := NewModel(ctx, 100, 20)
+	model.Init()
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+
+	assert.Equal(t, "jj abandon -r def", copied)
+	assert.Contains(t, model.View(), "copied to clipboard")
+}
@@ -0,0 +1,44 @@
+package common
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DialogStyles are the styles of the dialogs opened over the revisions, they are read from the
+// palette with the prefix of the dialog, e.g. "bookmarks title"
+type DialogStyles struct {
+	Border   lipgloss.Style
+	Title    lipgloss.Style
+	Text     lipgloss.Style
+	Dimmed   lipgloss.Style
+	Selected lipgloss.Style
+	Shortcut lipgloss.Style
+	Success  lipgloss.Style
+	Error    lipgloss.Style
+}
+
+func NewDialogStyles(prefix string) DialogStyles {
+	return DialogStyles{
+		Border:   DefaultPalette.GetBorder(prefix+" border", lipgloss.RoundedBorder()).Padding(0, 1),
+		Title:    DefaultPalette.Get(prefix + " title"),
+		Text:     DefaultPalette.Get(prefix + " text"),
+		Dimmed:   DefaultPalette.Get(prefix + " dimmed"),
+		Selected: DefaultPalette.Get(prefix + " selected"),
+		Shortcut: DefaultPalette.Get(prefix + " shortcut"),
+		Success:  DefaultPalette.Get(prefix + " success"),
+		Error:    DefaultPalette.Get(prefix + " error"),
+	}
+}
+
+// RenderHelp renders the keys of the dialog on a single line
+func (s DialogStyles) RenderHelp(bindings []key.Binding) string {
+	var entries []string
+	for _, binding := range bindings {
+		h := binding.Help()
+		entries = append(entries, s.Shortcut.Render(h.Key)+s.Dimmed.PaddingLeft(1).Render(h.Desc))
+	}
+	return strings.Join(entries, s.Dimmed.Render(" • "))
+}
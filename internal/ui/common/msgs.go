@@ -1,6 +1,7 @@
 package common
 
 import (
+	"errors"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -45,8 +46,32 @@ type (
 		RawFileOut   []byte // raw output from `jj file list`
 	}
 	ShowPreview bool
+	// CommandPaletteMsg opens the command palette in the status bar
+	CommandPaletteMsg struct{}
+	// PlayMacroMsg presses the keys one after the other, e.g. "D", "enter", "ctrl+s"
+	PlayMacroMsg struct {
+		Keys []string
+	}
+	// ModeActionMsg opens a mode with its key and presses the key of an action of the mode once
+	// the commands loading the mode are done, e.g. once the files of the details are listed
+	ModeActionMsg struct {
+		Mode   tea.KeyMsg
+		Action tea.KeyMsg
+	}
+	// ShowDetailsMsg opens the details of the revision with the file selected
+	ShowDetailsMsg struct {
+		Revision string
+		File     string
+	}
+	FlashMsg struct {
+		Text  string
+		Error bool
+	}
 )
 
+// ErrCommandCancelled is the error of a command which is cancelled by the user while running
+var ErrCommandCancelled = errors.New("command cancelled")
+
 type State int
 
 const (
@@ -55,6 +80,10 @@ const (
 	Error
 )
 
+// Tick creates the timers of the UI, the test harness replaces it to run the timers on a clock
+// of its own
+var Tick = tea.Tick
+
 func Close() tea.Msg {
 	return CloseViewMsg{}
 }
@@ -81,6 +110,10 @@ func ToggleHelp() tea.Msg {
 	return ToggleHelpMsg{}
 }
 
+func CommandPalette() tea.Msg {
+	return CommandPaletteMsg{}
+}
+
 func CommandRunning(args []string) tea.Cmd {
 	return func() tea.Msg {
 		command := "jj " + strings.Join(args, " ")
@@ -0,0 +1,12 @@
+package common
+
+import "os"
+
+// Shell returns the shell of the user, the command lines are run with `Shell() -c` so that they can
+// use pipes and redirections
+func Shell() string {
+	if program := os.Getenv("SHELL"); program != "" {
+		return program
+	}
+	return "sh"
+}
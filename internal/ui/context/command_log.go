@@ -0,0 +1,147 @@
+package context
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCommandLogEntries is the number of commands kept in the command log
+const maxCommandLogEntries = 500
+
+// maxCommandLogOutput is the length of the output of a command kept in the command log
+const maxCommandLogOutput = 4 * 1024
+
+// CommandLogEntry is a jj or shell command run during the session
+type CommandLogEntry struct {
+	Program string
+	Args    []string
+	// Immediate is true for the commands run in the background to load data
+	Immediate bool
+	// Interactive is true for the commands which are given the terminal
+	Interactive bool
+	Start       time.Time
+	Duration    time.Duration
+	ExitCode    int
+	Stdout      string
+	Stderr      string
+	// OperationId is the id of the operation created by the command, if any
+	OperationId string
+}
+
+// IsShell reports whether the entry is a command line run by the shell
+func (e CommandLogEntry) IsShell() bool {
+	return e.Program != "jj"
+}
+
+// String returns the command line of the entry
+func (e CommandLogEntry) String() string {
+	if e.IsShell() && len(e.Args) == 2 && e.Args[0] == "-c" {
+		return e.Args[1]
+	}
+	parts := []string{e.Program}
+	for _, arg := range e.Args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'|&;()<>$*?") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// CommandLog keeps the most recent commands run during the session
+type CommandLog struct {
+	entries []CommandLogEntry
+	mu      sync.RWMutex
+}
+
+func NewCommandLog() *CommandLog {
+	return &CommandLog{}
+}
+
+func (l *CommandLog) Add(entry CommandLogEntry) {
+	if l == nil {
+		return
+	}
+	entry.Stdout = truncateOutput(entry.Stdout)
+	entry.Stderr = truncateOutput(entry.Stderr)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+	if len(l.entries) > maxCommandLogEntries {
+		l.entries = l.entries[len(l.entries)-maxCommandLogEntries:]
+	}
+}
+
+// Run runs the command and adds it to the log with its output, which is returned as well. The
+// output written to the terminal is not captured, and the command is logged as interactive.
+func (l *CommandLog) Run(c *exec.Cmd, immediate bool) (string, string, error) {
+	var stdout, stderr strings.Builder
+	_, interactive := c.Stdout.(*os.File)
+	// a writer shared by the standard output and error keeps the order of the combined output
+	combined := c.Stdout != nil && c.Stdout == c.Stderr
+	c.Stdout = capture(c.Stdout, &stdout)
+	if combined {
+		c.Stderr = c.Stdout
+	} else {
+		c.Stderr = capture(c.Stderr, &stderr)
+	}
+	start := time.Now()
+	err := c.Run()
+	l.Add(CommandLogEntry{
+		Program:     c.Args[0],
+		Args:        c.Args[1:],
+		Immediate:   immediate,
+		Interactive: interactive,
+		Start:       start,
+		Duration:    time.Since(start),
+		ExitCode:    ExitCode(err),
+		Stdout:      stdout.String(),
+		Stderr:      stderr.String(),
+	})
+	return stdout.String(), stderr.String(), err
+}
+
+// capture copies what is written to the writer into the builder, unless it is a file
+func capture(w io.Writer, b *strings.Builder) io.Writer {
+	switch w.(type) {
+	case nil:
+		return b
+	case *os.File:
+		return w
+	}
+	return io.MultiWriter(w, b)
+}
+
+func truncateOutput(output string) string {
+	if len(output) <= maxCommandLogOutput {
+		return output
+	}
+	return output[:maxCommandLogOutput] + "\n... (truncated)"
+}
+
+// Entries returns a copy of the entries, oldest first
+func (l *CommandLog) Entries() []CommandLogEntry {
+	if l == nil {
+		return nil
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]CommandLogEntry(nil), l.entries...)
+}
+
+// ExitCode returns the exit code of the process which returned the error
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) {
+		return exitError.ExitCode()
+	}
+	return -1
+}
@@ -0,0 +1,96 @@
+package context
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandLogEntry_String(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry CommandLogEntry
+		want  string
+	}{
+		{
+			name:  "jj command",
+			entry: CommandLogEntry{Program: "jj", Args: []string{"new", "-r", "abc"}},
+			want:  "jj new -r abc",
+		},
+		{
+			name:  "quotes arguments with spaces",
+			entry: CommandLogEntry{Program: "jj", Args: []string{"describe", "-m", "it's done"}},
+			want:  `jj describe -m 'it'\''s done'`,
+		},
+		{
+			name:  "shell command",
+			entry: CommandLogEntry{Program: "/bin/sh", Args: []string{"-c", "echo $HOME | wc -c"}},
+			want:  "echo $HOME | wc -c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.entry.String())
+		})
+	}
+}
+
+func TestCommandLog_KeepsMostRecentEntries(t *testing.T) {
+	log := NewCommandLog()
+	for i := 0; i < maxCommandLogEntries+10; i++ {
+		log.Add(CommandLogEntry{Program: "jj", ExitCode: i})
+	}
+	entries := log.Entries()
+	assert.Len(t, entries, maxCommandLogEntries)
+	assert.Equal(t, 10, entries[0].ExitCode)
+	assert.Equal(t, maxCommandLogEntries+9, entries[len(entries)-1].ExitCode)
+}
+
+func TestCommandLog_NilIsEmpty(t *testing.T) {
+	var log *CommandLog
+	log.Add(CommandLogEntry{Program: "jj"})
+	assert.Empty(t, log.Entries())
+}
+
+func TestCommandLog_TruncatesOutput(t *testing.T) {
+	log := NewCommandLog()
+	log.Add(CommandLogEntry{Program: "jj", Stdout: strings.Repeat("a", maxCommandLogOutput+1)})
+	stdout := log.Entries()[0].Stdout
+	assert.True(t, strings.HasPrefix(stdout, strings.Repeat("a", maxCommandLogOutput)+"\n"))
+	assert.Less(t, len(stdout), maxCommandLogOutput+100)
+}
+
+func TestCommandLog_Run(t *testing.T) {
+	log := NewCommandLog()
+	stdout, stderr, err := log.Run(exec.Command("sh", "-c", "echo out; echo err >&2; exit 2"), true)
+	assert.Error(t, err)
+	assert.Equal(t, "out\n", stdout)
+	assert.Equal(t, "err\n", stderr)
+	entry := log.Entries()[0]
+	assert.Equal(t, "echo out; echo err >&2; exit 2", entry.String())
+	assert.Equal(t, 2, entry.ExitCode)
+	assert.True(t, entry.Immediate)
+	assert.Equal(t, "out\n", entry.Stdout)
+	assert.Equal(t, "err\n", entry.Stderr)
+}
+
+func TestCommandLog_RunCombinedOutput(t *testing.T) {
+	log := NewCommandLog()
+	var output bytes.Buffer
+	c := exec.Command("sh", "-c", "echo out; echo err >&2")
+	c.Stdout = &output
+	c.Stderr = &output
+	_, _, err := log.Run(c, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "out\nerr\n", output.String())
+	assert.Equal(t, "out\nerr\n", log.Entries()[0].Stdout)
+}
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, 0, ExitCode(nil))
+	assert.Equal(t, 3, ExitCode(exec.Command("sh", "-c", "exit 3").Run()))
+	assert.Equal(t, -1, ExitCode(exec.ErrNotFound))
+}
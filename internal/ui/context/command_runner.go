@@ -5,38 +5,146 @@ import (
 	"context"
 	"errors"
 	"github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
 	"io"
 	"log"
 	"os"
 	"os/exec"
 	"slices"
+	"strings"
 	"sync"
+	"time"
 )
 
 type CommandRunner interface {
+	// RunCommandImmediate runs the command and returns its output. A command which changes the
+	// repo waits for the running ones, so it must only be called from a tea.Cmd.
 	RunCommandImmediate(args []string) ([]byte, error)
 	RunCommandStreaming(ctx context.Context, args []string) (*StreamingCommand, error)
 	RunCommand(args []string, continuations ...tea.Cmd) tea.Cmd
 	RunInteractiveCommand(args []string, continuation tea.Cmd) tea.Cmd
+	// CancelCommands stops the commands started by RunCommand which are still running,
+	// and reports whether there were any
+	CancelCommands() bool
 }
 
+// cancelWaitDelay is how long a cancelled command is given to exit after being interrupted
+// before it is killed
+const cancelWaitDelay = 3 * time.Second
+
 type MainCommandRunner struct {
 	Location string
+	Log      *CommandLog
+	Jobs     *Jobs
+	Recorder *Recorder
+	running  map[int]context.CancelFunc
+	nextId   int
+	mu       sync.Mutex
 }
 
+// RunCommandImmediate runs the command and waits for it, the commands which change the repository
+// can be cancelled like the ones started by RunCommand
 func (a *MainCommandRunner) RunCommandImmediate(args []string) ([]byte, error) {
-	c := exec.Command("jj", args...)
+	ctx := context.Background()
+	readOnly := jj.CommandArgs(args).IsReadOnly()
+	var before string
+	if !readOnly {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		untrack := a.track(cancel)
+		defer untrack()
+		done, started := a.Jobs.Start(ctx, false)
+		if !started {
+			a.Recorder.Add(args, nil, common.ErrCommandCancelled)
+			return nil, common.ErrCommandCancelled
+		}
+		defer done()
+		before = a.logOperationId()
+	}
+	c := exec.CommandContext(ctx, "jj", args...)
 	c.Dir = a.Location
-	if output, err := c.Output(); err != nil {
-		var exitError *exec.ExitError
-		if errors.As(err, &exitError) {
-			return nil, errors.New(string(exitError.Stderr))
+	c.Cancel = func() error {
+		return c.Process.Signal(os.Interrupt)
+	}
+	c.WaitDelay = cancelWaitDelay
+	start := time.Now()
+	output, err := c.Output()
+	if ctx.Err() != nil {
+		err = common.ErrCommandCancelled
+	}
+	// only the commands loading data are hidden in the log, the ones changing the repository are shown
+	entry := CommandLogEntry{Program: "jj", Args: args, Immediate: readOnly, Start: start, Duration: time.Since(start), ExitCode: ExitCode(err), Stdout: string(output)}
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) {
+		entry.Stderr = string(exitError.Stderr)
+	}
+	a.record(entry, before)
+	if err != nil {
+		if exitError != nil && ctx.Err() == nil {
+			err = errors.New(string(exitError.Stderr))
 		}
-		return nil, err
+		output = nil
 	} else {
-		return bytes.Trim(output, "\n"), nil
+		output = bytes.Trim(output, "\n")
+	}
+	a.Recorder.Add(args, output, err)
+	return output, err
+}
+
+// logOperationId returns the id of the current operation without snapshotting the working copy,
+// it is only needed when the commands are logged
+func (a *MainCommandRunner) logOperationId() string {
+	if a.Log == nil {
+		return ""
 	}
+	c := exec.Command("jj", "op", "log", "--color", "never", "--quiet", "--no-graph", "--limit", "1", "--template", "id.short()", "--ignore-working-copy")
+	c.Dir = a.Location
+	output, _ := c.Output()
+	return strings.TrimSpace(string(output))
+}
+
+// record adds the command to the log. A command which succeeded in changing the repository is given
+// the operation it created, which is the current operation when it differs from the one before the
+// command. Both are read while the command holds its job so that no other command of jjui runs
+// between them.
+func (a *MainCommandRunner) record(entry CommandLogEntry, before string) {
+	if a.Log == nil {
+		return
+	}
+	if entry.ExitCode == 0 && !jj.CommandArgs(entry.Args).IsReadOnly() {
+		if id := a.logOperationId(); id != before {
+			entry.OperationId = id
+		}
+	}
+	a.Log.Add(entry)
+}
+
+// track keeps the cancel function of a running command until the returned function is called
+func (a *MainCommandRunner) track(cancel context.CancelFunc) func() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.running == nil {
+		a.running = make(map[int]context.CancelFunc)
+	}
+	id := a.nextId
+	a.nextId++
+	a.running[id] = cancel
+	return func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		delete(a.running, id)
+		cancel()
+	}
+}
+
+func (a *MainCommandRunner) CancelCommands() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, cancel := range a.running {
+		cancel()
+	}
+	return len(a.running) > 0
 }
 
 func (a *MainCommandRunner) RunCommandStreaming(ctx context.Context, args []string) (*StreamingCommand, error) {
@@ -51,62 +159,133 @@ func (a *MainCommandRunner) RunCommandStreaming(ctx context.Context, args []stri
 		return nil, err
 	}
 	if err = c.Start(); err != nil {
+		a.Recorder.Add(args, nil, err)
 		return nil, err
 	}
-	return &StreamingCommand{
+	command := &StreamingCommand{
 		ReadCloser: pipe,
 		ErrPipe:    errPipe,
 		cmd:        c,
 		ctx:        ctx,
-	}, nil
+	}
+	a.Recorder.recordStreaming(args, command)
+	return command, nil
 }
 
+// RunCommand runs the command and then the continuations, which are skipped when the command
+// is cancelled
 func (a *MainCommandRunner) RunCommand(args []string, continuations ...tea.Cmd) tea.Cmd {
-	commands := make([]tea.Cmd, 0)
-	commands = append(commands,
+	return tea.Batch(
+		common.CommandRunning(args),
 		func() tea.Msg {
-			if !slices.Contains(args, "--color") {
-				args = append(args, "--color", "always")
-			}
-			c := exec.Command("jj", args...)
-			c.Dir = a.Location
-			var output bytes.Buffer
-			c.Stderr = &output
-			_, err := c.Output()
-			if err != nil {
-				var exitError *exec.ExitError
-				if errors.As(err, &exitError) {
-					err = errors.New(output.String())
-				}
+			msg := a.run(args)
+			if errors.Is(msg.Err, common.ErrCommandCancelled) {
+				return msg
 			}
-			return common.CommandCompletedMsg{
-				Output: output.String(),
-				Err:    err,
-			}
-		})
-	commands = append(commands, continuations...)
+			commands := []tea.Cmd{func() tea.Msg { return msg }}
+			return tea.Sequence(append(commands, continuations...)...)()
+		},
+	)
+}
+
+// run runs the command until it completes or is cancelled
+func (a *MainCommandRunner) run(args []string) common.CommandCompletedMsg {
+	// commands are recorded as they are requested so that they can be found when replayed
+	recorded := args
+	if !slices.Contains(args, "--color") {
+		args = append(args, "--color", "always")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	untrack := a.track(cancel)
+	defer untrack()
+	done, started := a.Jobs.Start(ctx, jj.CommandArgs(args).IsReadOnly())
+	if !started {
+		a.Recorder.Add(recorded, nil, common.ErrCommandCancelled)
+		return common.CommandCompletedMsg{Err: common.ErrCommandCancelled}
+	}
+	c := exec.CommandContext(ctx, "jj", args...)
+	c.Dir = a.Location
+	// jj is interrupted rather than killed so that it can release the working copy lock
+	c.Cancel = func() error {
+		return c.Process.Signal(os.Interrupt)
+	}
+	c.WaitDelay = cancelWaitDelay
+	var output bytes.Buffer
+	c.Stderr = &output
+	var before string
+	if !jj.CommandArgs(args).IsReadOnly() {
+		before = a.logOperationId()
+	}
+	start := time.Now()
+	stdout, err := c.Output()
+	a.record(CommandLogEntry{Program: "jj", Args: args, Start: start, Duration: time.Since(start), ExitCode: ExitCode(err), Stdout: string(stdout), Stderr: output.String()}, before)
+	// the next job is started before the completion is reported so that the jobs are counted correctly
+	done()
+	if ctx.Err() != nil {
+		a.Recorder.Add(recorded, output.Bytes(), common.ErrCommandCancelled)
+		return common.CommandCompletedMsg{
+			Output: output.String(),
+			Err:    common.ErrCommandCancelled,
+		}
+	}
+	if err != nil {
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) {
+			err = errors.New(output.String())
+		}
+	}
+	a.Recorder.Add(recorded, output.Bytes(), err)
+	return common.CommandCompletedMsg{
+		Output: output.String(),
+		Err:    err,
+	}
+}
+
+// RunInteractiveCommand gives the terminal to the command once the running commands are done, the
+// commands started while it runs wait for it
+func (a *MainCommandRunner) RunInteractiveCommand(args []string, continuation tea.Cmd) tea.Cmd {
 	return tea.Batch(
 		common.CommandRunning(args),
-		tea.Sequence(commands...),
+		func() tea.Msg {
+			// the command can only be cancelled while it is waiting for its turn
+			ctx, cancel := context.WithCancel(context.Background())
+			untrack := a.track(cancel)
+			done, started := a.Jobs.Start(ctx, false)
+			untrack()
+			if !started {
+				a.Recorder.Add(args, nil, common.ErrCommandCancelled)
+				return common.CommandCompletedMsg{Err: common.ErrCommandCancelled}
+			}
+			return a.execInteractive(args, done, continuation)()
+		},
 	)
 }
 
-func (a *MainCommandRunner) RunInteractiveCommand(args []string, continuation tea.Cmd) tea.Cmd {
+func (a *MainCommandRunner) execInteractive(args []string, done func(), continuation tea.Cmd) tea.Cmd {
+	before := a.logOperationId()
 	c := exec.Command("jj", args...)
 	errBuffer := &bytes.Buffer{}
 	c.Stderr = errBuffer
 	c.Dir = a.Location
-	return tea.Batch(
-		common.CommandRunning(args),
-		tea.ExecProcess(c, func(err error) tea.Msg {
+	start := time.Now()
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		entry := CommandLogEntry{Program: "jj", Args: args, Interactive: true, Start: start, Duration: time.Since(start), ExitCode: ExitCode(err), Stderr: errBuffer.String()}
+		// the operation created by the command is looked up outside of the event loop
+		return tea.Sequence(func() tea.Msg {
+			a.record(entry, before)
+			done()
+			if err != nil {
+				err = errors.New(errBuffer.String())
+			}
+			a.Recorder.Add(args, nil, err)
 			if err != nil {
-				return common.CommandCompletedMsg{Err: errors.New(errBuffer.String())}
+				return common.CommandCompletedMsg{Err: err}
 			}
 			return tea.Batch(continuation, func() tea.Msg {
 				return common.CommandCompletedMsg{Err: nil}
 			})()
-		}),
-	)
+		})()
+	})
 }
 
 type StreamingCommand struct {
@@ -122,6 +301,11 @@ func (c *StreamingCommand) Close() error {
 	c.once.Do(func() {
 		log.Println("closing streaming command")
 		pipeErr := c.ReadCloser.Close()
+		// replayed commands have no process
+		if c.cmd == nil {
+			err = pipeErr
+			return
+		}
 
 		if c.ctx.Err() != nil {
 			log.Println("killing process due to context cancellation")
@@ -0,0 +1,162 @@
+package context
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeJJ puts a jj script running the given shell commands on the PATH
+func fakeJJ(t *testing.T, script string) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "jj"), []byte("#!/bin/sh\n"+script+"\n"), 0o755)
+	assert.NoError(t, err)
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestMainCommandRunner_CancelCommands(t *testing.T) {
+	fakeJJ(t, "exec sleep 10")
+	runner := &MainCommandRunner{Location: t.TempDir()}
+	assert.False(t, runner.CancelCommands())
+
+	completed := make(chan common.CommandCompletedMsg)
+	go func() {
+		completed <- runner.run([]string{"git", "fetch"})
+	}()
+	assert.Eventually(t, runner.CancelCommands, 3*time.Second, 10*time.Millisecond)
+
+	select {
+	case msg := <-completed:
+		assert.Equal(t, common.ErrCommandCancelled, msg.Err)
+	case <-time.After(cancelWaitDelay + 3*time.Second):
+		assert.Fail(t, "cancelled command did not complete")
+	}
+	assert.False(t, runner.CancelCommands())
+}
+
+func TestMainCommandRunner_CompletedCommandIsNotCancelled(t *testing.T) {
+	fakeJJ(t, "echo done >&2")
+	runner := &MainCommandRunner{Location: t.TempDir()}
+
+	msg := runner.run([]string{"git", "fetch"})
+	assert.NoError(t, msg.Err)
+	assert.Equal(t, "done\n", msg.Output)
+	assert.False(t, runner.CancelCommands())
+}
+
+func TestMainCommandRunner_CancelImmediateCommand(t *testing.T) {
+	fakeJJ(t, "exec sleep 10")
+	runner := &MainCommandRunner{Location: t.TempDir()}
+
+	completed := make(chan error)
+	go func() {
+		_, err := runner.RunCommandImmediate([]string{"git", "fetch"})
+		completed <- err
+	}()
+	assert.Eventually(t, runner.CancelCommands, 3*time.Second, 10*time.Millisecond)
+
+	select {
+	case err := <-completed:
+		assert.Equal(t, common.ErrCommandCancelled, err)
+	case <-time.After(cancelWaitDelay + 3*time.Second):
+		assert.Fail(t, "cancelled command did not complete")
+	}
+}
+
+func TestMainCommandRunner_CancelledCommandSkipsContinuations(t *testing.T) {
+	fakeJJ(t, "exec sleep 10")
+	runner := &MainCommandRunner{Location: t.TempDir()}
+	continued := false
+	cmd := runner.RunCommand([]string{"git", "fetch"}, func() tea.Msg {
+		continued = true
+		return nil
+	})
+
+	// the first command of the batch reports that the command is running
+	commands := cmd().(tea.BatchMsg)
+	completed := make(chan tea.Msg)
+	go func() {
+		completed <- commands[1]()
+	}()
+	assert.Eventually(t, runner.CancelCommands, 3*time.Second, 10*time.Millisecond)
+
+	select {
+	case msg := <-completed:
+		assert.Equal(t, common.CommandCompletedMsg{Err: common.ErrCommandCancelled}, msg)
+	case <-time.After(cancelWaitDelay + 3*time.Second):
+		assert.Fail(t, "cancelled command did not complete")
+	}
+	assert.False(t, continued)
+}
+
+func TestMainCommandRunner_InteractiveCommandWaitsForJobs(t *testing.T) {
+	runner := &MainCommandRunner{Location: t.TempDir(), Jobs: NewJobs()}
+	done, _ := runner.Jobs.Start(context.Background(), false)
+	defer done()
+
+	// the first command of the batch reports that the command is running
+	commands := runner.RunInteractiveCommand([]string{"split"}, nil)().(tea.BatchMsg)
+	completed := make(chan tea.Msg)
+	go func() {
+		completed <- commands[1]()
+	}()
+	assert.Eventually(t, func() bool {
+		_, queued := runner.Jobs.Counts()
+		return queued == 1
+	}, 3*time.Second, 10*time.Millisecond)
+	assert.True(t, runner.CancelCommands())
+	assert.Equal(t, common.CommandCompletedMsg{Err: common.ErrCommandCancelled}, <-completed)
+}
+
+func TestMainCommandRunner_OperationIdOfCommand(t *testing.T) {
+	dir := t.TempDir()
+	op := filepath.Join(dir, "op")
+	assert.NoError(t, os.WriteFile(op, []byte("1"), 0o644))
+	// new creates an operation, describe without changes doesn't
+	fakeJJ(t, `case "$1" in
+op) cat "`+op+`" ;;
+new) echo 2 > "`+op+`" ;;
+esac`)
+	runner := &MainCommandRunner{Location: dir, Log: NewCommandLog()}
+
+	runner.run([]string{"describe", "-m", "same"})
+	runner.run([]string{"new"})
+	// an operation created outside of jjui is not given to the next command
+	assert.NoError(t, os.WriteFile(op, []byte("3"), 0o644))
+	runner.run([]string{"describe", "-m", "same"})
+
+	entries := runner.Log.Entries()
+	assert.Len(t, entries, 3)
+	assert.Empty(t, entries[0].OperationId)
+	assert.Equal(t, "2", entries[1].OperationId)
+	assert.Empty(t, entries[2].OperationId)
+}
+
+func TestMainCommandRunner_ImmediateCommandChangingTheRepoIsShown(t *testing.T) {
+	dir := t.TempDir()
+	op := filepath.Join(dir, "op")
+	assert.NoError(t, os.WriteFile(op, []byte("1"), 0o644))
+	fakeJJ(t, `case "$1" in
+op) cat "`+op+`" ;;
+workspace) echo 2 > "`+op+`" ;;
+esac`)
+	runner := &MainCommandRunner{Location: dir, Log: NewCommandLog()}
+
+	_, err := runner.RunCommandImmediate([]string{"log", "-r", "@"})
+	assert.NoError(t, err)
+	_, err = runner.RunCommandImmediate([]string{"workspace", "add", "../other"})
+	assert.NoError(t, err)
+
+	entries := runner.Log.Entries()
+	assert.Len(t, entries, 2)
+	assert.True(t, entries[0].Immediate)
+	assert.Empty(t, entries[0].OperationId)
+	assert.False(t, entries[1].Immediate)
+	assert.Equal(t, "2", entries[1].OperationId)
+}
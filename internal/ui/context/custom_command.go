@@ -57,6 +57,13 @@ func LoadCustomCommands(output string) (map[string]CustomCommand, error) {
 			}
 			cmd.Name = name
 			registry[name] = cmd
+		} else if _, hasMacro := tempMap["macro"]; hasMacro {
+			var cmd CustomMacroCommand
+			if err := metadata.PrimitiveDecode(primitive, &cmd); err != nil {
+				return nil, fmt.Errorf("failed to decode macro command %s: %w", name, err)
+			}
+			cmd.Name = name
+			registry[name] = cmd
 		} else {
 			var cmd CustomRunCommand
 			if err := metadata.PrimitiveDecode(primitive, &cmd); err != nil {
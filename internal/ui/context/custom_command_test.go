@@ -2,6 +2,7 @@ package context
 
 import (
 	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -13,10 +14,11 @@ func TestLoad_CustomCommands(t *testing.T) {
 "restore evolog" = { key = ["ctrl+e"],  args = ["op", "restore", "-r", "$revision"] }
 "resolve vscode" = { key = ["ctrl+r"],  args = ["resolve", "--tool", "vscode"], show = "interactive" }
 "update revset" = { key = ["M"],  revset = "::$change_id" }
+"describe empty" = { key = ["ctrl+y"],  macro = ["D", "ctrl+s"] }
 `
 	registry, err := LoadCustomCommands(content)
 	assert.NoError(t, err)
-	assert.Len(t, registry, 4)
+	assert.Len(t, registry, 5)
 
 	testCases := []struct {
 		name        string
@@ -70,6 +72,18 @@ func TestLoad_CustomCommands(t *testing.T) {
 				assert.Equal(t, "update revset", revsetCmd.Name)
 			},
 		},
+		{
+			name:        "macro command",
+			commandName: "describe empty",
+			testFunc: func(t *testing.T, cmd CustomCommand) {
+				macroCmd, ok := cmd.(CustomMacroCommand)
+				assert.True(t, ok, "Command should be CustomMacroCommand")
+				assert.Equal(t, []string{"ctrl+y"}, macroCmd.Key)
+				assert.Equal(t, []string{"D", "ctrl+s"}, macroCmd.Macro)
+				assert.Equal(t, "describe empty", macroCmd.Name)
+				assert.Equal(t, common.PlayMacroMsg{Keys: []string{"D", "ctrl+s"}}, macroCmd.Prepare(nil)())
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,30 @@
+package context
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/ui/common"
+)
+
+// CustomMacroCommand replays its keys like a recorded macro, it is how the macros are kept in the
+// config
+type CustomMacroCommand struct {
+	CustomCommandBase
+	Macro []string `toml:"macro"`
+}
+
+func (c CustomMacroCommand) Description(*MainContext) string {
+	return fmt.Sprintf("press %s", strings.Join(c.Macro, " "))
+}
+
+func (c CustomMacroCommand) IsApplicableTo(SelectedItem) bool {
+	return true
+}
+
+func (c CustomMacroCommand) Prepare(*MainContext) tea.Cmd {
+	return func() tea.Msg {
+		return common.PlayMacroMsg{Keys: c.Macro}
+	}
+}
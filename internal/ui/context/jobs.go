@@ -0,0 +1,97 @@
+package context
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Jobs runs the commands which change the repo one at a time in the order they are started,
+// while the read-only commands run right away next to them
+type Jobs struct {
+	mu       sync.Mutex
+	busy     bool
+	running  int
+	waiting  []chan struct{}
+	readOnly int
+}
+
+func NewJobs() *Jobs {
+	return &Jobs{}
+}
+
+// Start waits for the turn of the job and returns the function to call when the job is done.
+// It returns false without starting the job when the context is cancelled while the job is queued.
+func (j *Jobs) Start(ctx context.Context, readOnly bool) (func(), bool) {
+	if j == nil {
+		return func() {}, true
+	}
+	j.mu.Lock()
+	if readOnly {
+		j.readOnly++
+		j.mu.Unlock()
+		return j.done(true), true
+	}
+	if !j.busy {
+		j.busy = true
+		j.running++
+		j.mu.Unlock()
+		return j.done(false), true
+	}
+	turn := make(chan struct{})
+	j.waiting = append(j.waiting, turn)
+	j.mu.Unlock()
+
+	select {
+	case <-turn:
+		return j.done(false), true
+	case <-ctx.Done():
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		if i := slices.Index(j.waiting, turn); i != -1 {
+			j.waiting = slices.Delete(j.waiting, i, i+1)
+		} else {
+			// the turn came while the job was being cancelled, it is passed to the next job
+			j.release()
+		}
+		return nil, false
+	}
+}
+
+func (j *Jobs) done(readOnly bool) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			j.mu.Lock()
+			defer j.mu.Unlock()
+			if readOnly {
+				j.readOnly--
+				return
+			}
+			j.release()
+		})
+	}
+}
+
+// release ends the running job and starts the next queued one, it must be called with the lock held
+func (j *Jobs) release() {
+	j.running--
+	if len(j.waiting) == 0 {
+		j.busy = false
+		return
+	}
+	next := j.waiting[0]
+	j.waiting = j.waiting[1:]
+	j.running++
+	close(next)
+}
+
+// Counts returns the number of running and queued jobs
+func (j *Jobs) Counts() (running int, queued int) {
+	if j == nil {
+		return 0, 0
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.running + j.readOnly, len(j.waiting)
+}
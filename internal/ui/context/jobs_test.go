@@ -0,0 +1,91 @@
+package context
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobs_RunsChangingJobsInOrder(t *testing.T) {
+	jobs := NewJobs()
+	done, started := jobs.Start(context.Background(), false)
+	assert.True(t, started)
+
+	var order []int
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 1; i <= 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			next, _ := jobs.Start(context.Background(), false)
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			next()
+		}()
+		// waits for the job to be queued to know the order
+		assert.Eventually(t, func() bool {
+			_, queued := jobs.Counts()
+			return queued == i
+		}, time.Second, time.Millisecond)
+	}
+
+	running, queued := jobs.Counts()
+	assert.Equal(t, 1, running)
+	assert.Equal(t, 3, queued)
+
+	done()
+	wg.Wait()
+	assert.Equal(t, []int{1, 2, 3}, order)
+	running, queued = jobs.Counts()
+	assert.Zero(t, running)
+	assert.Zero(t, queued)
+}
+
+func TestJobs_ReadOnlyJobsAreNotQueued(t *testing.T) {
+	jobs := NewJobs()
+	done, _ := jobs.Start(context.Background(), false)
+	defer done()
+
+	readDone, started := jobs.Start(context.Background(), true)
+	assert.True(t, started)
+	running, queued := jobs.Counts()
+	assert.Equal(t, 2, running)
+	assert.Zero(t, queued)
+
+	readDone()
+	readDone()
+	running, _ = jobs.Counts()
+	assert.Equal(t, 1, running)
+}
+
+func TestJobs_CancelledJobLeavesTheQueue(t *testing.T) {
+	jobs := NewJobs()
+	done, _ := jobs.Start(context.Background(), false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan bool)
+	go func() {
+		_, started := jobs.Start(ctx, false)
+		result <- started
+	}()
+	assert.Eventually(t, func() bool {
+		_, queued := jobs.Counts()
+		return queued == 1
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	assert.False(t, <-result)
+	done()
+
+	next, started := jobs.Start(context.Background(), false)
+	assert.True(t, started)
+	next()
+	running, queued := jobs.Counts()
+	assert.Zero(t, running)
+	assert.Zero(t, queued)
+}
@@ -63,15 +63,48 @@ type MainContext struct {
 	DefaultRevset  string
 	CurrentRevset  string
 	Histories      *config.Histories
+	Reviews        *Reviews
+	Statuses       *Statuses
+	CommandLog     *CommandLog
+	Jobs           *Jobs
 }
 
-func NewAppContext(location string) *MainContext {
+type Option func(*MainContext)
+
+// WithCommandRunner runs the jj commands with the runner, e.g. to replay a recording
+func WithCommandRunner(runner CommandRunner) Option {
+	return func(m *MainContext) {
+		m.CommandRunner = runner
+	}
+}
+
+// WithRecorder records the jj commands run in the repository
+func WithRecorder(recorder *Recorder) Option {
+	return func(m *MainContext) {
+		if runner, ok := m.CommandRunner.(*MainCommandRunner); ok {
+			runner.Recorder = recorder
+		}
+	}
+}
+
+func NewAppContext(location string, options ...Option) *MainContext {
+	commandLog := NewCommandLog()
+	jobs := NewJobs()
 	m := &MainContext{
 		CommandRunner: &MainCommandRunner{
 			Location: location,
+			Log:      commandLog,
+			Jobs:     jobs,
 		},
-		Location:  location,
-		Histories: config.NewHistories(),
+		CommandLog: commandLog,
+		Jobs:       jobs,
+		Location:   location,
+		Histories:  config.NewHistories(),
+		Reviews:    NewReviews(location),
+		Statuses:   NewStatuses(),
+	}
+	for _, option := range options {
+		option(m)
 	}
 
 	m.JJConfig = &config.JJConfig{}
@@ -0,0 +1,201 @@
+package context
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/ui/common"
+)
+
+// RecordEnv is the environment variable with the path of the file the jj commands are recorded to
+const RecordEnv = "JJUI_RECORD"
+
+// RecordedCommand is a jj invocation with what it returned to jjui, it is written to the
+// recording as a single line of JSON
+type RecordedCommand struct {
+	Args []string `json:"args"`
+	// Output is stdout for the commands whose output is used, and stderr for the others
+	Output string `json:"output,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (r RecordedCommand) err() error {
+	switch r.Error {
+	case "":
+		return nil
+	case common.ErrCommandCancelled.Error():
+		return common.ErrCommandCancelled
+	}
+	return errors.New(r.Error)
+}
+
+// Recorder writes the jj commands to a recording which can be replayed by ReplayCommandRunner
+type Recorder struct {
+	encoder *json.Encoder
+	mu      sync.Mutex
+}
+
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{encoder: json.NewEncoder(w)}
+}
+
+// Add records the command, it does nothing when the recorder is nil
+func (r *Recorder) Add(args []string, output []byte, err error) {
+	if r == nil {
+		return
+	}
+	command := RecordedCommand{Args: args, Output: string(output)}
+	if err != nil {
+		command.Error = err.Error()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_ = r.encoder.Encode(command)
+}
+
+// recordingReader records the output of a streaming command when it is read to the end or closed,
+// the output of a command which is closed early is recorded up to where it was read
+type recordingReader struct {
+	io.ReadCloser
+	recorder *Recorder
+	args     []string
+	stdout   bytes.Buffer
+	stderr   *bytes.Buffer
+	once     sync.Once
+}
+
+func (r *recordingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.stdout.Write(p[:n])
+	if err == io.EOF {
+		r.record()
+	}
+	return n, err
+}
+
+func (r *recordingReader) Close() error {
+	r.record()
+	return r.ReadCloser.Close()
+}
+
+func (r *recordingReader) record() {
+	r.once.Do(func() {
+		var err error
+		if stderr := r.stderr.String(); stderr != "" {
+			err = errors.New(stderr)
+		}
+		r.recorder.Add(r.args, r.stdout.Bytes(), err)
+	})
+}
+
+// recordStreaming records the output of the streaming command as it is read
+func (r *Recorder) recordStreaming(args []string, command *StreamingCommand) {
+	if r == nil {
+		return
+	}
+	stderr := &bytes.Buffer{}
+	command.ErrPipe = struct {
+		io.Reader
+		io.Closer
+	}{io.TeeReader(command.ErrPipe, stderr), command.ErrPipe}
+	command.ReadCloser = &recordingReader{ReadCloser: command.ReadCloser, recorder: r, args: args, stderr: stderr}
+}
+
+// ReplayCommandRunner serves the jj commands from a recording instead of running them. The
+// recordings of the same command are served in the order they were recorded, and the last one
+// is repeated once they run out.
+type ReplayCommandRunner struct {
+	recordings map[string][]RecordedCommand
+	mu         sync.Mutex
+}
+
+func NewReplayCommandRunner(r io.Reader) (*ReplayCommandRunner, error) {
+	runner := &ReplayCommandRunner{recordings: make(map[string][]RecordedCommand)}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var command RecordedCommand
+		if err := json.Unmarshal(scanner.Bytes(), &command); err != nil {
+			return nil, fmt.Errorf("invalid recording at line %d: %w", line, err)
+		}
+		key := replayKey(command.Args)
+		runner.recordings[key] = append(runner.recordings[key], command)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return runner, nil
+}
+
+func replayKey(args []string) string {
+	return strings.Join(args, "\x00")
+}
+
+func (r *ReplayCommandRunner) next(args []string) RecordedCommand {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := replayKey(args)
+	recordings := r.recordings[key]
+	if len(recordings) == 0 {
+		return RecordedCommand{Args: args, Error: fmt.Sprintf("no recording of jj %s", strings.Join(args, " "))}
+	}
+	if len(recordings) > 1 {
+		r.recordings[key] = recordings[1:]
+	}
+	return recordings[0]
+}
+
+func (r *ReplayCommandRunner) RunCommandImmediate(args []string) ([]byte, error) {
+	recorded := r.next(args)
+	return []byte(recorded.Output), recorded.err()
+}
+
+func (r *ReplayCommandRunner) RunCommandStreaming(_ context.Context, args []string) (*StreamingCommand, error) {
+	recorded := r.next(args)
+	return &StreamingCommand{
+		ReadCloser: io.NopCloser(strings.NewReader(recorded.Output)),
+		ErrPipe:    io.NopCloser(strings.NewReader(recorded.Error)),
+	}, nil
+}
+
+func (r *ReplayCommandRunner) RunCommand(args []string, continuations ...tea.Cmd) tea.Cmd {
+	commands := []tea.Cmd{func() tea.Msg {
+		return r.complete(args)
+	}}
+	commands = append(commands, continuations...)
+	return tea.Batch(common.CommandRunning(args), tea.Sequence(commands...))
+}
+
+func (r *ReplayCommandRunner) complete(args []string) tea.Msg {
+	recorded := r.next(args)
+	return common.CommandCompletedMsg{Output: recorded.Output, Err: recorded.err()}
+}
+
+func (r *ReplayCommandRunner) RunInteractiveCommand(args []string, continuation tea.Cmd) tea.Cmd {
+	return tea.Batch(common.CommandRunning(args), func() tea.Msg {
+		recorded := r.next(args)
+		if err := recorded.err(); err != nil {
+			return common.CommandCompletedMsg{Err: err}
+		}
+		return tea.Batch(continuation, func() tea.Msg {
+			return common.CommandCompletedMsg{Err: nil}
+		})()
+	})
+}
+
+func (r *ReplayCommandRunner) CancelCommands() bool {
+	return false
+}
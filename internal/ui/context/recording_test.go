@@ -0,0 +1,83 @@
+package context
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_ReplaysRecordedCommands(t *testing.T) {
+	fakeJJ(t, `case "$1" in
+  log) echo "@ kkmpptxz" ;;
+  git) echo "Error: no remote" >&2; exit 1 ;;
+esac`)
+	var recording bytes.Buffer
+	runner := &MainCommandRunner{Location: t.TempDir(), Recorder: NewRecorder(&recording)}
+
+	output, err := runner.RunCommandImmediate([]string{"log"})
+	require.NoError(t, err)
+	completed := runner.run([]string{"git", "fetch"})
+	require.Error(t, completed.Err)
+
+	replay, err := NewReplayCommandRunner(&recording)
+	require.NoError(t, err)
+
+	replayed, err := replay.RunCommandImmediate([]string{"log"})
+	assert.NoError(t, err)
+	assert.Equal(t, output, replayed)
+
+	assert.Equal(t, completed, replay.complete([]string{"git", "fetch"}))
+}
+
+func TestRecorder_RecordsStreamingCommands(t *testing.T) {
+	fakeJJ(t, `printf "line 1\nline 2\n"`)
+	var recording bytes.Buffer
+	runner := &MainCommandRunner{Location: t.TempDir(), Recorder: NewRecorder(&recording)}
+
+	command, err := runner.RunCommandStreaming(context.Background(), []string{"log"})
+	require.NoError(t, err)
+	output, err := io.ReadAll(command)
+	require.NoError(t, err)
+	require.NoError(t, command.Close())
+
+	replay, err := NewReplayCommandRunner(&recording)
+	require.NoError(t, err)
+	replayed, err := replay.RunCommandStreaming(context.Background(), []string{"log"})
+	require.NoError(t, err)
+	replayedOutput, err := io.ReadAll(replayed)
+	require.NoError(t, err)
+	assert.Equal(t, "line 1\nline 2\n", string(replayedOutput))
+	assert.Equal(t, output, replayedOutput)
+	assert.NoError(t, replayed.Close())
+}
+
+func TestReplayCommandRunner_ServesRecordingsInOrder(t *testing.T) {
+	recording := `{"args":["log"],"output":"first"}
+{"args":["log"],"output":"second"}
+{"args":["op","log"],"error":"Error: broken"}
+`
+	replay, err := NewReplayCommandRunner(strings.NewReader(recording))
+	require.NoError(t, err)
+
+	for _, expected := range []string{"first", "second", "second"} {
+		output, err := replay.RunCommandImmediate([]string{"log"})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(output))
+	}
+
+	_, err = replay.RunCommandImmediate([]string{"op", "log"})
+	assert.EqualError(t, err, "Error: broken")
+
+	_, err = replay.RunCommandImmediate([]string{"status"})
+	assert.EqualError(t, err, "no recording of jj status")
+}
+
+func TestReplayCommandRunner_InvalidRecording(t *testing.T) {
+	_, err := NewReplayCommandRunner(strings.NewReader("{\"args\":[\"log\"]}\nnot json\n"))
+	assert.ErrorContains(t, err, "invalid recording at line 2")
+}
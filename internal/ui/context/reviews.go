@@ -0,0 +1,102 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Reviews keeps the ids of the code reviews submitted for each full change id of the repository.
+// They are stored in the .jj directory, one `change_id<tab>review` pair per line.
+type Reviews struct {
+	file    string
+	ids     map[string]string
+	commits map[string]string
+	mu      sync.Mutex
+}
+
+func NewReviews(location string) *Reviews {
+	r := &Reviews{
+		file: filepath.Join(location, ".jj", "jjui", "reviews"),
+		ids:  make(map[string]string),
+	}
+	data, err := os.ReadFile(r.file)
+	if err != nil {
+		return r
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if changeId, review, ok := strings.Cut(line, "\t"); ok && changeId != "" {
+			r.ids[changeId] = review
+		}
+	}
+	return r
+}
+
+// Get returns the review of the full change id
+func (r *Reviews) Get(changeId string) string {
+	if r == nil || changeId == "" {
+		return ""
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ids[changeId]
+}
+
+// ChangeIds returns the full change ids which have a review
+func (r *Reviews) ChangeIds() []string {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]string, 0, len(r.ids))
+	for id := range r.ids {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// SetCommits replaces the full change ids of the revisions in the log, keyed by their commit ids
+// as they are shown in the log
+func (r *Reviews) SetCommits(changeIds map[string]string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commits = changeIds
+}
+
+// ForCommit returns the review of a revision in the log by its full change id
+func (r *Reviews) ForCommit(commitId string) string {
+	if r == nil || commitId == "" {
+		return ""
+	}
+	r.mu.Lock()
+	changeId := r.commits[commitId]
+	r.mu.Unlock()
+	return r.Get(changeId)
+}
+
+// Set records the review of the change id and writes all reviews to the disk
+func (r *Reviews) Set(changeId string, review string) error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ids[changeId] = review
+
+	var lines []string
+	for id, review := range r.ids {
+		lines = append(lines, id+"\t"+review)
+	}
+	slices.Sort(lines)
+	if err := os.MkdirAll(filepath.Dir(r.file), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.file, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
@@ -0,0 +1,37 @@
+package context
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviews_SetAndReload(t *testing.T) {
+	location := t.TempDir()
+	reviews := NewReviews(location)
+	assert.Empty(t, reviews.Get("kmtnvwpxlzsruqqlnsxpmtywkquqqkuo"))
+	assert.NoError(t, reviews.Set("kmtnvwpxlzsruqqlnsxpmtywkquqqkuo", "https://review.example.com/42"))
+
+	reloaded := NewReviews(location)
+	assert.Equal(t, "https://review.example.com/42", reloaded.Get("kmtnvwpxlzsruqqlnsxpmtywkquqqkuo"))
+	assert.Equal(t, []string{"kmtnvwpxlzsruqqlnsxpmtywkquqqkuo"}, reloaded.ChangeIds())
+	assert.Empty(t, reloaded.Get("zqrsxlyoqmtlvnwkrnzqkqnrxoxkmlzl"))
+}
+
+func TestReviews_OnlyFullChangeIdsMatch(t *testing.T) {
+	reviews := NewReviews(t.TempDir())
+	assert.NoError(t, reviews.Set("kmtnvwpxlzsruqqlnsxpmtywkquqqkuo", "42"))
+	assert.Empty(t, reviews.Get("kmtn"))
+	assert.Empty(t, reviews.Get("kmtnvwpxlzsruqqlnsxpmtywkquqqkuozz"))
+
+	reviews.SetCommits(map[string]string{"8b1e95e3": "kmtnvwpxlzsruqqlnsxpmtywkquqqkuo"})
+	assert.Equal(t, "42", reviews.ForCommit("8b1e95e3"))
+	assert.Empty(t, reviews.ForCommit("8b1e"))
+}
+
+func TestReviews_Nil(t *testing.T) {
+	var reviews *Reviews
+	assert.Empty(t, reviews.Get("kmtnvwpx"))
+	assert.Empty(t, reviews.ForCommit("8b1e95e3"))
+	assert.NoError(t, reviews.Set("kmtnvwpx", "42"))
+}
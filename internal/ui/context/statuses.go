@@ -0,0 +1,82 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/idursun/jjui/internal/ui/common"
+)
+
+const (
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+	StatusPending = "pending"
+)
+
+// CommitStatus is a CI or review status of a commit as reported by the status command
+type CommitStatus struct {
+	CommitId string `json:"commit_id"`
+	State    string `json:"state"`
+	Label    string `json:"label"`
+}
+
+// Statuses caches the statuses reported by the configured status command
+type Statuses struct {
+	byCommit map[string][]CommitStatus
+	mu       sync.RWMutex
+}
+
+func NewStatuses() *Statuses {
+	return &Statuses{byCommit: make(map[string][]CommitStatus)}
+}
+
+// Get returns the statuses of the commit, the commit id can be a shortened prefix
+func (s *Statuses) Get(commitId string) []CommitStatus {
+	if s == nil || commitId == "" {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if statuses, ok := s.byCommit[commitId]; ok {
+		return statuses
+	}
+	for id, statuses := range s.byCommit {
+		if strings.HasPrefix(id, commitId) {
+			return statuses
+		}
+	}
+	return nil
+}
+
+// Set replaces the cached statuses
+func (s *Statuses) Set(statuses []CommitStatus) {
+	if s == nil {
+		return
+	}
+	byCommit := make(map[string][]CommitStatus)
+	for _, status := range statuses {
+		byCommit[status.CommitId] = append(byCommit[status.CommitId], status)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byCommit = byCommit
+}
+
+// FetchStatuses runs the status command with the commit ids on stdin and parses the JSON it prints
+func FetchStatuses(log *CommandLog, location string, command string, commitIds []string) ([]CommitStatus, error) {
+	c := exec.Command(common.Shell(), "-c", command)
+	c.Dir = location
+	c.Stdin = strings.NewReader(strings.Join(commitIds, "\n") + "\n")
+	output, stderr, err := log.Run(c, true)
+	if err != nil {
+		return nil, fmt.Errorf("status command failed: %w: %s", err, strings.TrimSpace(stderr))
+	}
+	var statuses []CommitStatus
+	if err := json.Unmarshal([]byte(output), &statuses); err != nil {
+		return nil, fmt.Errorf("status command printed invalid JSON: %w", err)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,32 @@
+package context
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchStatuses(t *testing.T) {
+	command := `while read id; do printf '{"commit_id": "%s", "state": "success", "label": "ci"},' "$id"; done | sed 's/^/[/; s/,$/]/'`
+	statuses, err := FetchStatuses(nil, t.TempDir(), command, []string{"abc123", "def456"})
+	assert.NoError(t, err)
+	assert.Equal(t, []CommitStatus{
+		{CommitId: "abc123", State: StatusSuccess, Label: "ci"},
+		{CommitId: "def456", State: StatusSuccess, Label: "ci"},
+	}, statuses)
+}
+
+func TestFetchStatuses_InvalidJSON(t *testing.T) {
+	_, err := FetchStatuses(nil, t.TempDir(), "echo not json", []string{"abc123"})
+	assert.ErrorContains(t, err, "invalid JSON")
+}
+
+func TestStatuses_GetByPrefix(t *testing.T) {
+	statuses := NewStatuses()
+	statuses.Set([]CommitStatus{
+		{CommitId: "abc123", State: StatusFailure, Label: "ci"},
+		{CommitId: "abc123", State: StatusPending, Label: "review"},
+	})
+	assert.Len(t, statuses.Get("abc"), 2)
+	assert.Empty(t, statuses.Get("def"))
+}
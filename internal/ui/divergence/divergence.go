@@ -0,0 +1,342 @@
+package divergence
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+)
+
+// maximum number of divergent changes listed above the commits
+const listHeight = 5
+
+type updateChangesMsg struct {
+	changes []*jj.DivergentChange
+	err     error
+}
+
+type detailsMsg struct {
+	commitId string
+	evolog   string
+	diff     string
+}
+
+type details struct {
+	evolog string
+	diff   string
+}
+
+type styles struct {
+	common.DialogStyles
+	changeId lipgloss.Style
+}
+
+type Model struct {
+	context *context.MainContext
+	keyMap  config.KeyMappings[key.Binding]
+	changes []*jj.DivergentChange
+	details map[string]*details
+	err     error
+	loaded  bool
+	cursor  int
+	side    int
+	offset  int
+	width   int
+	height  int
+	styles  styles
+}
+
+func (m *Model) Width() int {
+	return m.width
+}
+
+func (m *Model) Height() int {
+	return m.height
+}
+
+func (m *Model) SetWidth(w int) {
+	m.width = w
+}
+
+func (m *Model) SetHeight(h int) {
+	m.height = h
+}
+
+func (m *Model) ShortHelp() []key.Binding {
+	return []key.Binding{
+		m.keyMap.Up,
+		m.keyMap.Down,
+		m.keyMap.Divergence.Prev,
+		m.keyMap.Divergence.Next,
+		m.keyMap.Divergence.Abandon,
+		m.keyMap.Divergence.Squash,
+		m.keyMap.Divergence.NewChangeId,
+		m.keyMap.Cancel,
+	}
+}
+
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.load
+}
+
+func (m *Model) load() tea.Msg {
+	output, err := m.context.RunCommandImmediate(jj.Divergent())
+	if err != nil {
+		return updateChangesMsg{err: err}
+	}
+	return updateChangesMsg{changes: jj.ParseDivergentOutput(string(output))}
+}
+
+// loadDetails fetches the evolog and the diff of the commits of the selected change
+// which haven't been loaded yet
+func (m *Model) loadDetails() tea.Cmd {
+	change := m.selectedChange()
+	if change == nil {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, c := range change.Commits {
+		commitId := c.Commit.CommitId
+		if _, ok := m.details[commitId]; ok {
+			continue
+		}
+		m.details[commitId] = &details{}
+		cmds = append(cmds, func() tea.Msg {
+			evolog, _ := m.context.RunCommandImmediate(jj.Evolog(commitId))
+			diff, _ := m.context.RunCommandImmediate(jj.Diff(commitId, ""))
+			return detailsMsg{commitId: commitId, evolog: string(evolog), diff: string(diff)}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) selectedChange() *jj.DivergentChange {
+	if m.cursor < 0 || m.cursor >= len(m.changes) {
+		return nil
+	}
+	return m.changes[m.cursor]
+}
+
+func (m *Model) selectedCommit() *jj.DivergentCommit {
+	change := m.selectedChange()
+	if change == nil || m.side >= len(change.Commits) {
+		return nil
+	}
+	return change.Commits[m.side]
+}
+
+func (m *Model) others() []*jj.Commit {
+	var others []*jj.Commit
+	for i, c := range m.selectedChange().Commits {
+		if i != m.side {
+			others = append(others, c.Commit)
+		}
+	}
+	return others
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case updateChangesMsg:
+		m.loaded = true
+		m.err = msg.err
+		m.changes = msg.changes
+		m.details = make(map[string]*details)
+		m.cursor = min(m.cursor, max(len(m.changes)-1, 0))
+		m.side = 0
+		m.offset = 0
+		return m, m.loadDetails()
+	case detailsMsg:
+		m.details[msg.commitId] = &details{evolog: msg.evolog, diff: msg.diff}
+		return m, nil
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keyMap.Cancel):
+			return m, common.Close
+		case key.Matches(msg, m.keyMap.Up):
+			if m.cursor > 0 {
+				m.cursor--
+				m.side = 0
+				m.offset = 0
+			}
+			return m, m.loadDetails()
+		case key.Matches(msg, m.keyMap.Down):
+			if m.cursor < len(m.changes)-1 {
+				m.cursor++
+				m.side = 0
+				m.offset = 0
+			}
+			return m, m.loadDetails()
+		case key.Matches(msg, m.keyMap.Divergence.Next):
+			if change := m.selectedChange(); change != nil {
+				m.side = (m.side + 1) % len(change.Commits)
+			}
+		case key.Matches(msg, m.keyMap.Divergence.Prev):
+			if change := m.selectedChange(); change != nil {
+				m.side = (m.side + len(change.Commits) - 1) % len(change.Commits)
+			}
+		case key.Matches(msg, m.keyMap.Preview.ScrollDown):
+			m.offset++
+		case key.Matches(msg, m.keyMap.Preview.ScrollUp):
+			m.offset = max(m.offset-1, 0)
+		case key.Matches(msg, m.keyMap.Preview.HalfPageDown):
+			m.offset += m.bodyHeight() / 2
+		case key.Matches(msg, m.keyMap.Preview.HalfPageUp):
+			m.offset = max(m.offset-m.bodyHeight()/2, 0)
+		case key.Matches(msg, m.keyMap.Divergence.Abandon):
+			if selected := m.selectedCommit(); selected != nil {
+				return m, m.context.RunCommand(jj.Abandon(jj.NewSelectedRevisions(selected.Commit)), common.Refresh, m.load)
+			}
+		case key.Matches(msg, m.keyMap.Divergence.Squash):
+			if selected := m.selectedCommit(); selected != nil {
+				// squashing may need to open the editor to combine the descriptions
+				squash := jj.Squash(jj.NewSelectedRevisions(m.others()...), selected.Commit.GetChangeId(), false, false)
+				return m, m.context.RunInteractiveCommand(squash, tea.Batch(common.Refresh, m.load))
+			}
+		case key.Matches(msg, m.keyMap.Divergence.NewChangeId):
+			if selected := m.selectedCommit(); selected != nil {
+				return m, m.context.RunCommand(jj.MetaEdit(jj.NewSelectedRevisions(selected.Commit), "--update-change-id"), common.Refresh, m.load)
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) contentWidth() int {
+	return max(m.width-m.styles.Border.GetHorizontalFrameSize(), 20)
+}
+
+func (m *Model) listHeight() int {
+	return min(len(m.changes), listHeight)
+}
+
+// bodyHeight is the number of lines left for the commit columns
+func (m *Model) bodyHeight() int {
+	// title, blank lines around the list and the help line
+	const chrome = 5
+	return max(m.height-m.styles.Border.GetVerticalFrameSize()-m.listHeight()-chrome, 3)
+}
+
+func (m *Model) View() string {
+	width := m.contentWidth()
+	var lines []string
+	lines = append(lines, m.styles.Title.Render("Divergent changes"), "")
+	switch {
+	case m.err != nil:
+		lines = append(lines, m.styles.Error.Render(strings.TrimSpace(m.err.Error())))
+	case !m.loaded:
+		lines = append(lines, m.styles.Dimmed.Render("loading..."))
+	case len(m.changes) == 0:
+		lines = append(lines, m.styles.Dimmed.Render("There are no divergent changes"))
+	default:
+		lines = append(lines, m.renderList(width)...)
+		lines = append(lines, "", m.renderColumns(width))
+	}
+	lines = append(lines, "", m.styles.RenderHelp(m.ShortHelp()))
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	content = lipgloss.Place(width, lipgloss.Height(content), 0, 0, content, lipgloss.WithWhitespaceBackground(m.styles.Text.GetBackground()))
+	return m.styles.Border.Render(content)
+}
+
+func (m *Model) renderList(width int) []string {
+	start := max(m.cursor-listHeight+1, 0)
+	end := min(start+listHeight, len(m.changes))
+	var lines []string
+	for i := start; i < end; i++ {
+		change := m.changes[i]
+		style := m.styles.Text
+		if i == m.cursor {
+			style = m.styles.Selected
+		}
+		line := lipgloss.JoinHorizontal(0,
+			m.styles.changeId.Inherit(style).Render(change.ChangeId),
+			style.Render(fmt.Sprintf(" %d commits ", len(change.Commits))),
+			style.Render(change.Commits[0].Description),
+		)
+		lines = append(lines, lipgloss.NewStyle().Inherit(style).Width(width).MaxWidth(width).Render(line))
+	}
+	return lines
+}
+
+func (m *Model) renderColumns(width int) string {
+	change := m.selectedChange()
+	n := len(change.Commits)
+	separator := m.styles.Dimmed.Render(" │ ")
+	columnWidth := max((width-(n-1)*lipgloss.Width(separator))/n, 10)
+	height := m.bodyHeight()
+
+	var columns []string
+	for i, c := range change.Commits {
+		if i > 0 {
+			columns = append(columns, strings.TrimSuffix(strings.Repeat(separator+"\n", height), "\n"))
+		}
+		columns = append(columns, m.renderColumn(c, i == m.side, columnWidth, height))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+}
+
+func (m *Model) renderColumn(c *jj.DivergentCommit, selected bool, width int, height int) string {
+	marker := "  "
+	headerStyle := m.styles.Text
+	if selected {
+		marker = "▶ "
+		headerStyle = m.styles.Selected
+	}
+	description := c.Description
+	if description == "" {
+		description = "(no description set)"
+	}
+	header := []string{
+		headerStyle.Render(marker) + m.styles.changeId.Render(c.Commit.CommitId) + m.styles.Dimmed.Render(" "+c.Author+" "+c.Timestamp),
+		headerStyle.Render(marker + description),
+		"",
+	}
+
+	var body []string
+	if d, ok := m.details[c.Commit.CommitId]; ok {
+		body = append(body, m.styles.Title.Render("Evolog"))
+		body = append(body, strings.Split(strings.ReplaceAll(d.evolog, "\r", ""), "\n")...)
+		body = append(body, "", m.styles.Title.Render("Diff"))
+		body = append(body, strings.Split(strings.ReplaceAll(d.diff, "\r", ""), "\n")...)
+	}
+	wrapped := strings.Split(lipgloss.NewStyle().Width(width).Render(strings.Join(body, "\n")), "\n")
+	offset := min(m.offset, max(len(wrapped)-1, 0))
+	wrapped = wrapped[offset:]
+
+	lines := append(header, wrapped...)
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	cell := lipgloss.NewStyle().Width(width).MaxWidth(width)
+	for i, line := range lines {
+		lines[i] = cell.Render(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func NewModel(c *context.MainContext, width int, height int) *Model {
+	return &Model{
+		context: c,
+		keyMap:  config.Current.GetKeyMap(),
+		details: make(map[string]*details),
+		width:   width,
+		height:  height,
+		styles: styles{
+			DialogStyles: common.NewDialogStyles("divergence"),
+			changeId:     common.DefaultPalette.Get("divergence change_id"),
+		},
+	}
+}
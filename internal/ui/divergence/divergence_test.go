@@ -0,0 +1,72 @@
+package divergence
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/test"
+)
+
+const divergentOutput = `kmtnvwpx;0a1b2c3d;jane@example.com;2 hours ago;fix: handle empty input
+kmtnvwpx;4e5f6a7b;jane@example.com;3 hours ago;fix: handle empty input`
+
+func expectDetails(commandRunner *test.CommandRunner, commitIds ...string) {
+	for _, commitId := range commitIds {
+		commandRunner.Expect(jj.Evolog(commitId))
+		commandRunner.Expect(jj.Diff(commitId, ""))
+	}
+}
+
+func Test_AbandonSelectedCommit(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Divergent()).SetOutput([]byte(divergentOutput))
+	expectDetails(commandRunner, "0a1b2c3d", "4e5f6a7b")
+	commandRunner.Expect(jj.Abandon(jj.NewSelectedRevisions(&jj.Commit{ChangeId: "kmtnvwpx??", CommitId: "4e5f6a7b"})))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), 100, 30)
+	tm := teatest.NewTestModel(t, test.NewShell(model))
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("4e5f6a7b"))
+	})
+	tm.Send(tea.KeyMsg{Type: tea.KeyTab})
+	tm.Type("a")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
+func Test_SquashOthersIntoSelectedCommit(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Divergent()).SetOutput([]byte(divergentOutput))
+	expectDetails(commandRunner, "0a1b2c3d", "4e5f6a7b")
+	others := jj.NewSelectedRevisions(&jj.Commit{ChangeId: "kmtnvwpx??", CommitId: "4e5f6a7b"})
+	commandRunner.Expect(jj.Squash(others, "0a1b2c3d", false, false))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), 100, 30)
+	tm := teatest.NewTestModel(t, test.NewShell(model))
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("kmtnvwpx"))
+	})
+	tm.Type("s")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
+func Test_NoDivergentChanges(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Divergent())
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), 100, 30)
+	tm := teatest.NewTestModel(t, test.NewShell(model))
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("no divergent changes"))
+	})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
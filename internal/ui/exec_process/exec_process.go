@@ -36,16 +36,12 @@ func ExecLine(ctx *context.MainContext, msg common.ExecMsg) tea.Cmd {
 	case common.ExecJJ:
 		args := strings.Fields(msg.Line)
 		args = jj.TemplatedArgs(args, replacements)
-		return exec_program("jj", args, nil)
+		return exec_program(ctx.CommandLog, "jj", args, nil)
 	case common.ExecShell:
 		// user input is run via `$SHELL -c` to support user specifying command lines
 		// that have pipes (eg, to a pager) or redirection.
-		program := os.Getenv("SHELL")
-		if len(program) == 0 {
-			program = "sh"
-		}
 		args := []string{"-c", msg.Line}
-		return exec_program(program, args, replacements)
+		return exec_program(ctx.CommandLog, common.Shell(), args, replacements)
 	}
 	return nil
 }
@@ -61,14 +57,15 @@ func ExecLine(ctx *context.MainContext, msg common.ExecMsg) tea.Cmd {
 // CommandCompleted machinery we use for background jj processes.
 // However if the program fails we ask the user for confirmation before closing
 // and returning stdio back to jjui.
-func exec_program(program string, args []string, env map[string]string) tea.Cmd {
-	p := &process{program: program, args: args, env: env}
+func exec_program(log *context.CommandLog, program string, args []string, env map[string]string) tea.Cmd {
+	p := &process{log: log, program: program, args: args, env: env}
 	return tea.Exec(p, func(err error) tea.Msg {
 		return common.RefreshMsg{}
 	})
 }
 
 type process struct {
+	log     *context.CommandLog
 	program string
 	args    []string
 	stdin   io.Reader
@@ -100,7 +97,7 @@ func (p *process) Run() error {
 		askUserClose = false
 	}()
 
-	err := cmd.Run()
+	_, _, err := p.log.Run(cmd, false)
 	// Dont auto-close on error.
 	if askUserClose || err != nil {
 		p.stderr.Write([]byte("\njjui: press enter to continue... "))
@@ -1,6 +1,7 @@
 package flash
 
 import (
+	"errors"
 	"github.com/idursun/jjui/internal/screen"
 	"strings"
 	"time"
@@ -47,15 +48,28 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 		}
 		return m, nil
 	case common.CommandCompletedMsg:
+		// cancellation is reported by the status bar
+		if errors.Is(msg.Err, common.ErrCommandCancelled) {
+			return m, nil
+		}
 		id := m.add(msg.Output, msg.Err)
 		if msg.Err == nil {
-			return m, tea.Tick(expiringMessageTimeout, func(t time.Time) tea.Msg {
+			return m, common.Tick(expiringMessageTimeout, func(t time.Time) tea.Msg {
 				return expireMessageMsg{id: id}
 			})
 		}
 		return m, nil
 	case common.UpdateRevisionsFailedMsg:
 		m.add(msg.Output, msg.Err)
+	case common.FlashMsg:
+		if msg.Error {
+			m.add("", errors.New(msg.Text))
+			return m, nil
+		}
+		id := m.add(msg.Text, nil)
+		return m, common.Tick(expiringMessageTimeout, func(t time.Time) tea.Msg {
+			return expireMessageMsg{id: id}
+		})
 	}
 	return m, nil
 }
@@ -46,8 +46,6 @@ var debounceDuration = 250 * time.Millisecond
 
 type debouncePreview int
 
-type initMsg struct{}
-
 func newCmd(msg tea.Msg) tea.Cmd {
 	return func() tea.Msg {
 		return msg
@@ -55,12 +53,12 @@ func newCmd(msg tea.Msg) tea.Cmd {
 }
 
 func (fzf *fuzzyFiles) Init() tea.Cmd {
-	return newCmd(initMsg{})
+	return fuzzy_search.Init()
 }
 
 func (fzf *fuzzyFiles) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
-	case initMsg:
+	case fuzzy_search.InitMsg:
 		fzf.search("")
 	case fuzzy_search.SearchMsg:
 		if cmd := fzf.handleKey(msg.Pressed); cmd != nil {
@@ -70,7 +68,7 @@ func (fzf *fuzzyFiles) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if fzf.revsetPreview {
 			fzf.debounceTag++
 			tag := debouncePreview(fzf.debounceTag)
-			return fzf, tea.Tick(debounceDuration, func(_ time.Time) tea.Msg {
+			return fzf, common.Tick(debounceDuration, func(_ time.Time) tea.Msg {
 				return tag
 			})
 		}
@@ -99,22 +97,6 @@ func (fzf *fuzzyFiles) updateRevSet() tea.Cmd {
 	return common.UpdateRevSet(revset)
 }
 
-func (fzf *fuzzyFiles) isInputMovement(k tea.KeyMsg) bool {
-	return key.Matches(k,
-		fzf.inputKm.CharacterForward,
-		fzf.inputKm.CharacterBackward,
-		fzf.inputKm.WordForward,
-		fzf.inputKm.WordBackward,
-		fzf.inputKm.LineStart,
-		fzf.inputKm.LineEnd,
-		fzf.inputKm.AcceptSuggestion,
-	)
-}
-
-func skipSearch() tea.Msg {
-	return nil
-}
-
 func (fzf *fuzzyFiles) handleKey(msg tea.KeyMsg) tea.Cmd {
 	fzfKm := fzf.keyMap.FileSearch
 	previewKm := fzf.keyMap.Preview
@@ -128,11 +110,11 @@ func (fzf *fuzzyFiles) handleKey(msg tea.KeyMsg) tea.Cmd {
 	} else {
 		switch {
 		case key.Matches(msg, fzfKm.Up, previewKm.ScrollUp):
-			fzf.moveCursor(1)
-			return skipSearch
+			fzf.cursor = fuzzy_search.MoveCursor(fzf.cursor, 1, fzf.matches)
+			return fuzzy_search.SkipSearch
 		case key.Matches(msg, fzfKm.Down, previewKm.ScrollDown):
-			fzf.moveCursor(-1)
-			return skipSearch
+			fzf.cursor = fuzzy_search.MoveCursor(fzf.cursor, -1, fzf.matches)
+			return fuzzy_search.SkipSearch
 		}
 	}
 
@@ -156,25 +138,13 @@ func (fzf *fuzzyFiles) handleKey(msg tea.KeyMsg) tea.Cmd {
 		)
 	case key.Matches(msg, fzfKm.Accept, fzf.inputKm.AcceptSuggestion):
 		return fzf.updateRevSet()
-	case fzf.isInputMovement(msg):
-		return skipSearch
+	case fuzzy_search.IsInputMovement(fzf.inputKm, msg):
+		return fuzzy_search.SkipSearch
 	}
 
 	return nil
 }
 
-func (fzf *fuzzyFiles) moveCursor(inc int) {
-	n := fzf.cursor + inc
-	l := len(fzf.matches) - 1
-	if n > l {
-		n = 0
-	}
-	if n < 0 {
-		n = l
-	}
-	fzf.cursor = n
-}
-
 func (fzf *fuzzyFiles) Styles() fuzzy_search.Styles {
 	return fzf.styles
 }
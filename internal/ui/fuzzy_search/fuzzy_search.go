@@ -4,6 +4,8 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/idursun/jjui/internal/ui/common"
@@ -32,6 +34,48 @@ type SearchMsg struct {
 	Pressed tea.KeyMsg
 }
 
+// InitMsg makes a model run the search with an empty input when it is opened
+type InitMsg struct{}
+
+// Init is the Init of the models, it sends InitMsg
+func Init() tea.Cmd {
+	return func() tea.Msg {
+		return InitMsg{}
+	}
+}
+
+// SkipSearch is returned for the keys which are handled without searching the input again
+func SkipSearch() tea.Msg {
+	return nil
+}
+
+// IsInputMovement reports whether the key only moves the cursor of the input, the input is not
+// searched again then
+func IsInputMovement(km textinput.KeyMap, k tea.KeyMsg) bool {
+	return key.Matches(k,
+		km.CharacterForward,
+		km.CharacterBackward,
+		km.WordForward,
+		km.WordBackward,
+		km.LineStart,
+		km.LineEnd,
+		km.AcceptSuggestion,
+	)
+}
+
+// MoveCursor returns the cursor moved by inc, it wraps around the matches
+func MoveCursor(cursor int, inc int, matches fuzzy.Matches) int {
+	n := cursor + inc
+	l := len(matches) - 1
+	if n > l {
+		n = 0
+	}
+	if n < 0 {
+		n = l
+	}
+	return n
+}
+
 func NewStyles() Styles {
 	return Styles{
 		Dimmed:        common.DefaultPalette.Get("status dimmed"),
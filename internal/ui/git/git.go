@@ -10,6 +10,7 @@ import (
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/common/menu"
 	"github.com/idursun/jjui/internal/ui/context"
+	"slices"
 )
 
 type itemCategory string
@@ -43,10 +44,16 @@ func (i item) Description() string {
 	return i.desc
 }
 
+type remotesLoadedMsg struct {
+	remotes []jj.Remote
+}
+
 type Model struct {
 	context *context.MainContext
 	keymap  config.KeyMappings[key.Binding]
 	menu    menu.Menu
+	width   int
+	height  int
 }
 
 func (m *Model) Width() int {
@@ -58,19 +65,26 @@ func (m *Model) Height() int {
 }
 
 func (m *Model) SetWidth(w int) {
+	m.width = w
 	m.menu.SetWidth(w)
 }
 
 func (m *Model) SetHeight(h int) {
+	m.height = h
 	m.menu.SetHeight(h)
 }
 
 func (m *Model) Init() tea.Cmd {
-	return nil
+	return func() tea.Msg {
+		return remotesLoadedMsg{remotes: loadRemotes(m.context)}
+	}
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case remotesLoadedMsg:
+		m.addRemoteItems(msg.remotes)
+		return m, m.menu.Filtered(m.menu.Filter)
 	case tea.KeyMsg:
 		if m.menu.List.SettingFilter() {
 			break
@@ -78,7 +92,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch {
 		case key.Matches(msg, m.keymap.Apply):
 			action := m.menu.List.SelectedItem().(item)
-			return m, m.context.RunCommand(jj.Args(action.command...), common.Refresh, common.Close)
+			// the menu is closed right away so that the revisions can be browsed while the command runs
+			return m, tea.Batch(common.Close, m.context.RunCommand(jj.Args(action.command...), common.Refresh))
 		case key.Matches(msg, m.keymap.Cancel):
 			if m.menu.Filter != "" || m.menu.List.IsFiltered() {
 				m.menu.List.ResetFilter()
@@ -89,10 +104,15 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.filtered(string(itemCategoryPush))
 		case key.Matches(msg, m.keymap.Git.Fetch) && m.menu.Filter != string(itemCategoryFetch):
 			return m.filtered(string(itemCategoryFetch))
+		case key.Matches(msg, m.keymap.Git.Sync):
+			return m, tea.Batch(common.Close, Sync(m.context))
+		case key.Matches(msg, m.keymap.Git.Remotes):
+			remotes := NewRemotesModel(m.context, m.width, m.height)
+			return remotes, remotes.Init()
 		default:
 			for _, listItem := range m.menu.List.Items() {
 				if item, ok := listItem.(item); ok && m.menu.Filter != "" && item.key == msg.String() {
-					return m, m.context.RunCommand(jj.Args(item.command...), common.Refresh, common.Close)
+					return m, tea.Batch(common.Close, m.context.RunCommand(jj.Args(item.command...), common.Refresh))
 				}
 			}
 		}
@@ -102,6 +122,34 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// addRemoteItems adds the push items of the remotes after the other push items, and their fetch
+// items after the other fetch items
+func (m *Model) addRemoteItems(remotes []jj.Remote) {
+	var push, fetch []list.Item
+	for _, remote := range remotes {
+		push = append(push, item{
+			name:     fmt.Sprintf("git push --remote %s", remote.Name),
+			desc:     fmt.Sprintf("Push tracking bookmarks in the current revset to %s", remote.URL),
+			command:  jj.GitPush("--remote", remote.Name),
+			category: itemCategoryPush,
+		})
+		fetch = append(fetch, item{
+			name:     fmt.Sprintf("git fetch --remote %s", remote.Name),
+			desc:     fmt.Sprintf("Fetch from %s", remote.URL),
+			command:  jj.GitFetch("--remote", remote.Name),
+			category: itemCategoryFetch,
+		})
+	}
+	index := slices.IndexFunc(m.menu.Items, func(i list.Item) bool {
+		return i.(item).category == itemCategoryFetch
+	})
+	if index == -1 {
+		index = len(m.menu.Items)
+	}
+	m.menu.Items = slices.Insert(m.menu.Items, index, push...)
+	m.menu.Items = append(m.menu.Items, fetch...)
+}
+
 func (m *Model) filtered(filter string) (tea.Model, tea.Cmd) {
 	return m, m.menu.Filtered(filter)
 }
@@ -110,6 +158,8 @@ func (m *Model) View() string {
 	helpKeys := []key.Binding{
 		m.keymap.Git.Push,
 		m.keymap.Git.Fetch,
+		m.keymap.Git.Remotes,
+		m.keymap.Git.Sync,
 	}
 
 	return m.menu.View(helpKeys)
@@ -166,6 +216,8 @@ func NewModel(c *context.MainContext, commit *jj.Commit, width int, height int)
 		item{name: "git push --deleted", desc: "Push all deleted bookmarks", command: jj.GitPush("--deleted"), category: itemCategoryPush, key: "d"},
 		item{name: "git push --tracked", desc: "Push all tracked bookmarks (including deleted bookmarks)", command: jj.GitPush("--tracked"), category: itemCategoryPush, key: "t"},
 		item{name: "git push --allow-new", desc: "Allow pushing new bookmarks", command: jj.GitPush("--allow-new"), category: itemCategoryPush},
+	)
+	items = append(items,
 		item{name: "git fetch", desc: "Fetch from remote", command: jj.GitFetch(), category: itemCategoryFetch, key: "f"},
 		item{name: "git fetch --all-remotes", desc: "Fetch from all remotes", command: jj.GitFetch("--all-remotes"), category: itemCategoryFetch, key: "a"},
 	)
@@ -1,17 +1,20 @@
 package git
 
 import (
+	"bytes"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/exp/teatest"
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
+	"slices"
 	"testing"
 	"time"
 )
 
 func Test_Push(t *testing.T) {
 	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList())
 	commandRunner.Expect(jj.GitPush())
 	defer commandRunner.Verify()
 
@@ -23,6 +26,7 @@ func Test_Push(t *testing.T) {
 
 func Test_Fetch(t *testing.T) {
 	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList())
 	commandRunner.Expect(jj.GitFetch())
 	defer commandRunner.Verify()
 
@@ -56,6 +60,7 @@ func Test_PushChange(t *testing.T) {
 	commandRunner := test.NewTestCommandRunner(t)
 	// Expect bookmark list to be loaded since we have a changeId
 	commandRunner.Expect(jj.BookmarkList(changeId)).SetOutput([]byte(""))
+	commandRunner.Expect(jj.GitRemoteList())
 	commandRunner.Expect(jj.GitPush("--change", changeId))
 	defer commandRunner.Verify()
 
@@ -68,3 +73,73 @@ func Test_PushChange(t *testing.T) {
 	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
 	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
 }
+
+func Test_FetchFromRemote(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte("origin git@github.com:idursun/jjui.git\nupstream https://example.com/jjui.git"))
+	commandRunner.Expect(jj.GitFetch("--remote", "upstream"))
+	defer commandRunner.Verify()
+
+	op := NewModel(test.NewTestContext(commandRunner), nil, 0, 0)
+	tm := teatest.NewTestModel(t, test.NewShell(op))
+	tm.Type("/")
+	tm.Type("fetch --remote upstream")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
+func Test_Remotes_Add(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList())
+	commandRunner.Expect(jj.GitRemoteAdd("upstream", "https://example.com/jjui.git"))
+	defer commandRunner.Verify()
+
+	op := NewRemotesModel(test.NewTestContext(commandRunner), 0, 0)
+	tm := teatest.NewTestModel(t, test.NewShell(op))
+	tm.Type("a")
+	tm.Type("upstream")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.Type("https://example.com/jjui.git")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
+func Test_Remotes_Remove(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte("origin git@github.com:idursun/jjui.git"))
+	commandRunner.Expect(jj.GitRemoteRemove("origin"))
+	defer commandRunner.Verify()
+
+	op := NewRemotesModel(test.NewTestContext(commandRunner), 0, 0)
+	tm := teatest.NewTestModel(t, test.NewShell(op))
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("origin"))
+	})
+	tm.Type("d")
+	tm.Type("y")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
+func Test_RemotesAreLoadedInInit(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte("origin git@github.com:idursun/jjui.git"))
+	defer commandRunner.Verify()
+
+	op := NewModel(test.NewTestContext(commandRunner), nil, 0, 0)
+	var names []string
+	for _, i := range op.menu.Items {
+		names = append(names, i.(item).name)
+	}
+	assert.NotContains(t, names, "git fetch --remote origin")
+
+	op.Update(op.Init()())
+	names = nil
+	for _, i := range op.menu.Items {
+		names = append(names, i.(item).name)
+	}
+	assert.Equal(t, "git push --remote origin", names[slices.Index(names, "git fetch")-1])
+	assert.Equal(t, "git fetch --remote origin", names[len(names)-1])
+}
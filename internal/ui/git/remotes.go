@@ -0,0 +1,268 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/common/menu"
+	"github.com/idursun/jjui/internal/ui/confirmation"
+	"github.com/idursun/jjui/internal/ui/context"
+)
+
+type updateRemotesMsg struct {
+	items []list.Item
+}
+
+type remoteItem struct {
+	jj.Remote
+}
+
+func (i remoteItem) ShortCut() string {
+	return ""
+}
+
+func (i remoteItem) FilterValue() string {
+	return i.Name
+}
+
+func (i remoteItem) Title() string {
+	return i.Name
+}
+
+func (i remoteItem) Description() string {
+	return i.URL
+}
+
+// prompt asks for the values of one or more fields before running a command built from them
+type prompt struct {
+	title   string
+	labels  []string
+	values  []string
+	input   textinput.Model
+	command func(values []string) jj.CommandArgs
+}
+
+func (p *prompt) step() int {
+	return len(p.values)
+}
+
+type RemotesModel struct {
+	context      *context.MainContext
+	keymap       config.KeyMappings[key.Binding]
+	menu         menu.Menu
+	prompt       *prompt
+	confirmation tea.Model
+	styles       remoteStyles
+}
+
+type remoteStyles struct {
+	border lipgloss.Style
+	title  lipgloss.Style
+	text   lipgloss.Style
+	dimmed lipgloss.Style
+}
+
+func (m *RemotesModel) Width() int {
+	return m.menu.Width()
+}
+
+func (m *RemotesModel) Height() int {
+	return m.menu.Height()
+}
+
+func (m *RemotesModel) SetWidth(w int) {
+	m.menu.SetWidth(w)
+}
+
+func (m *RemotesModel) SetHeight(h int) {
+	m.menu.SetHeight(h)
+}
+
+func (m *RemotesModel) Init() tea.Cmd {
+	return m.load
+}
+
+func (m *RemotesModel) load() tea.Msg {
+	var items []list.Item
+	for _, remote := range loadRemotes(m.context) {
+		items = append(items, remoteItem{remote})
+	}
+	return updateRemotesMsg{items: items}
+}
+
+func (m *RemotesModel) selected() *remoteItem {
+	if selected, ok := m.menu.List.SelectedItem().(remoteItem); ok {
+		return &selected
+	}
+	return nil
+}
+
+// run executes the command and reloads the remotes, keeping the panel open
+func (m *RemotesModel) run(args jj.CommandArgs, continuations ...tea.Cmd) tea.Cmd {
+	continuations = append(continuations, common.Refresh, m.load)
+	return m.context.RunCommand(args, continuations...)
+}
+
+func (m *RemotesModel) newPrompt(title string, labels []string, value string, command func(values []string) jj.CommandArgs) tea.Cmd {
+	input := textinput.New()
+	input.Prompt = labels[0] + ": "
+	input.CharLimit = 256
+	input.PromptStyle = m.styles.dimmed
+	input.TextStyle = m.styles.text
+	input.Cursor.TextStyle = m.styles.text
+	input.SetValue(value)
+	m.prompt = &prompt{
+		title:   title,
+		labels:  labels,
+		input:   input,
+		command: command,
+	}
+	return m.prompt.input.Focus()
+}
+
+func (m *RemotesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case confirmation.CloseMsg:
+		m.confirmation = nil
+		return m, nil
+	case updateRemotesMsg:
+		m.menu.Items = msg.items
+		return m, m.menu.List.SetItems(m.menu.Items)
+	}
+	if m.confirmation != nil {
+		var cmd tea.Cmd
+		m.confirmation, cmd = m.confirmation.Update(msg)
+		return m, cmd
+	}
+	if m.prompt != nil {
+		return m.updatePrompt(msg)
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.menu.List.SettingFilter() {
+			break
+		}
+		switch {
+		case key.Matches(msg, m.keymap.Cancel):
+			if m.menu.List.IsFiltered() {
+				m.menu.List.ResetFilter()
+				return m, nil
+			}
+			return m, common.Close
+		case key.Matches(msg, m.keymap.GitRemote.Add):
+			return m, m.newPrompt("Add remote", []string{"name", "url"}, "", func(values []string) jj.CommandArgs {
+				return jj.GitRemoteAdd(values[0], values[1])
+			})
+		case key.Matches(msg, m.keymap.GitRemote.Rename):
+			if remote := m.selected(); remote != nil {
+				return m, m.newPrompt(fmt.Sprintf("Rename remote %s", remote.Name), []string{"new name"}, remote.Name, func(values []string) jj.CommandArgs {
+					return jj.GitRemoteRename(remote.Name, values[0])
+				})
+			}
+		case key.Matches(msg, m.keymap.GitRemote.SetUrl):
+			if remote := m.selected(); remote != nil {
+				return m, m.newPrompt(fmt.Sprintf("Set url of remote %s", remote.Name), []string{"url"}, remote.URL, func(values []string) jj.CommandArgs {
+					return jj.GitRemoteSetUrl(remote.Name, values[0])
+				})
+			}
+		case key.Matches(msg, m.keymap.GitRemote.Remove):
+			if remote := m.selected(); remote != nil {
+				model := confirmation.New(
+					[]string{fmt.Sprintf("Are you sure you want to remove remote '%s' and its remote bookmarks?", remote.Name)},
+					confirmation.WithStylePrefix("git"),
+					confirmation.WithOption("Yes", m.run(jj.GitRemoteRemove(remote.Name), confirmation.Close), key.NewBinding(key.WithKeys("y"))),
+					confirmation.WithOption("No", confirmation.Close, key.NewBinding(key.WithKeys("n", "esc"))),
+				)
+				m.confirmation = &model
+				return m, m.confirmation.Init()
+			}
+		}
+	}
+	var cmd tea.Cmd
+	m.menu.List, cmd = m.menu.List.Update(msg)
+	return m, cmd
+}
+
+func (m *RemotesModel) updatePrompt(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keymap.Cancel):
+			m.prompt = nil
+			return m, nil
+		case key.Matches(msg, m.keymap.Apply):
+			value := strings.TrimSpace(m.prompt.input.Value())
+			if value == "" {
+				return m, nil
+			}
+			m.prompt.values = append(m.prompt.values, value)
+			if m.prompt.step() == len(m.prompt.labels) {
+				args := m.prompt.command(m.prompt.values)
+				m.prompt = nil
+				return m, m.run(args)
+			}
+			m.prompt.input.Prompt = m.prompt.labels[m.prompt.step()] + ": "
+			m.prompt.input.SetValue("")
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.prompt.input, cmd = m.prompt.input.Update(msg)
+	return m, cmd
+}
+
+func (m *RemotesModel) View() string {
+	if m.confirmation != nil {
+		return m.confirmation.View()
+	}
+	if m.prompt != nil {
+		content := lipgloss.JoinVertical(0, m.styles.title.Render(m.prompt.title), "", m.prompt.input.View())
+		content = lipgloss.Place(m.menu.Width(), lipgloss.Height(content), 0, 0, content)
+		return m.styles.border.Render(m.styles.text.Width(m.menu.Width()).Render(content))
+	}
+	helpKeys := []key.Binding{
+		m.keymap.GitRemote.Add,
+		m.keymap.GitRemote.Remove,
+		m.keymap.GitRemote.Rename,
+		m.keymap.GitRemote.SetUrl,
+	}
+	return m.menu.View(helpKeys)
+}
+
+func loadRemotes(c context.CommandRunner) []jj.Remote {
+	output, err := c.RunCommandImmediate(jj.GitRemoteList())
+	if err != nil {
+		return nil
+	}
+	return jj.ParseRemoteListOutput(string(output))
+}
+
+func NewRemotesModel(c *context.MainContext, width int, height int) *RemotesModel {
+	var items []list.Item
+	keymap := config.Current.GetKeyMap()
+	menu := menu.NewMenu(items, width, height, keymap, menu.WithStylePrefix("git"))
+	menu.Title = "Git Remotes"
+
+	m := &RemotesModel{
+		context: c,
+		keymap:  keymap,
+		menu:    menu,
+		styles: remoteStyles{
+			border: common.DefaultPalette.GetBorder("git menu border", lipgloss.NormalBorder()),
+			title:  common.DefaultPalette.Get("git menu title").Padding(0, 1, 0, 1),
+			text:   common.DefaultPalette.Get("git menu text"),
+			dimmed: common.DefaultPalette.Get("git menu dimmed"),
+		},
+	}
+	m.SetWidth(width)
+	m.SetHeight(height)
+	return m
+}
@@ -0,0 +1,177 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+)
+
+const (
+	// roots of my stacks which are not based on trunk() yet
+	stackRootsRevset = "roots(mine() & mutable() ~ ::trunk()) ~ trunk()+"
+	syncDestination  = "trunk()"
+)
+
+type stackResult struct {
+	root      string
+	size      int
+	conflicts int
+	err       error
+}
+
+type syncResult struct {
+	moved     []stackResult
+	conflict  []stackResult
+	abandoned []stackResult
+	failed    []stackResult
+}
+
+func (r syncResult) String() string {
+	var b strings.Builder
+	b.WriteString("Fetched from remotes and rebased stacks onto " + syncDestination + "\n")
+	write := func(title string, stacks []stackResult, format func(s stackResult) string) {
+		if len(stacks) == 0 {
+			return
+		}
+		b.WriteString("\n" + title + ":\n")
+		for _, s := range stacks {
+			b.WriteString("  " + s.root + " " + format(s) + "\n")
+		}
+	}
+	write("Moved", r.moved, func(s stackResult) string {
+		return fmt.Sprintf("(%d revisions)", s.size)
+	})
+	write("Conflicted", r.conflict, func(s stackResult) string {
+		return fmt.Sprintf("(%d of %d revisions have conflicts)", s.conflicts, s.size)
+	})
+	write("Abandoned, landed upstream", r.abandoned, func(s stackResult) string {
+		return fmt.Sprintf("(%d revisions)", s.size)
+	})
+	write("Failed", r.failed, func(s stackResult) string {
+		return strings.TrimSpace(s.err.Error())
+	})
+	if len(r.moved)+len(r.conflict)+len(r.abandoned)+len(r.failed) == 0 {
+		b.WriteString("\nAll stacks are already up to date\n")
+	}
+	return b.String()
+}
+
+func revisionsOf(runner context.CommandRunner, revset string) ([]string, error) {
+	output, err := runner.RunCommandImmediate(jj.GetIdsFromRevset(revset))
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			ids = append(ids, line)
+		}
+	}
+	return ids, nil
+}
+
+// syncStack rebases the stack starting at root onto trunk() and abandons the changes which became
+// empty when all of its changes have already landed upstream. The changes which were already empty
+// and the working copies are never abandoned.
+func syncStack(runner context.CommandRunner, root string, result *syncResult) {
+	stack := stackResult{root: root}
+	descendants := fmt.Sprintf("%s::", root)
+	nonEmptyBefore, err := revisionsOf(runner, fmt.Sprintf("%s ~ empty() ~ working_copies()", descendants))
+	if err != nil {
+		stack.err = err
+		result.failed = append(result.failed, stack)
+		return
+	}
+
+	from := jj.NewSelectedRevisions(&jj.Commit{ChangeId: root})
+	if _, err := runner.RunCommandImmediate(jj.Rebase(from, syncDestination, "--source", "--destination")); err != nil {
+		stack.err = err
+		result.failed = append(result.failed, stack)
+		return
+	}
+
+	ids, err := revisionsOf(runner, descendants)
+	if err != nil {
+		stack.err = err
+		result.failed = append(result.failed, stack)
+		return
+	}
+	stack.size = len(ids)
+
+	nonEmpty, err := revisionsOf(runner, fmt.Sprintf("%s ~ empty()", descendants))
+	if err != nil {
+		stack.err = err
+		result.failed = append(result.failed, stack)
+		return
+	}
+	if len(nonEmpty) == 0 && len(nonEmptyBefore) > 0 {
+		var commits []*jj.Commit
+		for _, id := range nonEmptyBefore {
+			commits = append(commits, &jj.Commit{ChangeId: id})
+		}
+		if _, err := runner.RunCommandImmediate(jj.Abandon(jj.NewSelectedRevisions(commits...))); err != nil {
+			stack.err = err
+			result.failed = append(result.failed, stack)
+			return
+		}
+		stack.size = len(commits)
+		result.abandoned = append(result.abandoned, stack)
+		return
+	}
+
+	conflicts, _ := revisionsOf(runner, fmt.Sprintf("%s & conflicts()", descendants))
+	if stack.conflicts = len(conflicts); stack.conflicts > 0 {
+		result.conflict = append(result.conflict, stack)
+		return
+	}
+	result.moved = append(result.moved, stack)
+}
+
+func runSync(runner context.CommandRunner) (syncResult, error) {
+	var result syncResult
+	if _, err := runner.RunCommandImmediate(jj.GitFetch()); err != nil {
+		return result, err
+	}
+	roots, err := revisionsOf(runner, stackRootsRevset)
+	if err != nil {
+		return result, err
+	}
+	for _, root := range roots {
+		syncStack(runner, root, &result)
+		// the remaining stacks are left as they are once the sync is cancelled
+		if n := len(result.failed); n > 0 && errors.Is(result.failed[n-1].err, common.ErrCommandCancelled) {
+			return result, common.ErrCommandCancelled
+		}
+	}
+	return result, nil
+}
+
+// Sync fetches from the remotes, rebases each of my stacks onto trunk() and shows a summary
+func Sync(c *context.MainContext) tea.Cmd {
+	var summary string
+	return tea.Sequence(
+		func() tea.Msg {
+			return common.CommandRunningMsg("sync: fetch and rebase stacks onto " + syncDestination)
+		},
+		func() tea.Msg {
+			result, err := runSync(c)
+			if err != nil {
+				return common.CommandCompletedMsg{Err: err}
+			}
+			summary = result.String()
+			return common.CommandCompletedMsg{}
+		},
+		common.Refresh,
+		func() tea.Msg {
+			if summary == "" {
+				return nil
+			}
+			return common.ShowDiffMsg(summary)
+		},
+	)
+}
@@ -0,0 +1,81 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func expectStack(commandRunner *test.CommandRunner, root string, nonEmptyBefore string, ids string, nonEmpty string, conflicts string) {
+	commandRunner.Expect(jj.GetIdsFromRevset(root + ":: ~ empty() ~ working_copies()")).SetOutput([]byte(nonEmptyBefore))
+	commandRunner.Expect(jj.Rebase(jj.NewSelectedRevisions(&jj.Commit{ChangeId: root}), "trunk()", "--source", "--destination"))
+	commandRunner.Expect(jj.GetIdsFromRevset(root + "::")).SetOutput([]byte(ids))
+	commandRunner.Expect(jj.GetIdsFromRevset(root + ":: ~ empty()")).SetOutput([]byte(nonEmpty))
+	if nonEmpty != "" || nonEmptyBefore == "" {
+		commandRunner.Expect(jj.GetIdsFromRevset(root + ":: & conflicts()")).SetOutput([]byte(conflicts))
+	}
+}
+
+func Test_runSync(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitFetch())
+	commandRunner.Expect(jj.GetIdsFromRevset(stackRootsRevset)).SetOutput([]byte("a\nb\nc\n"))
+	expectStack(commandRunner, "a", "a\na2", "a\na2", "a\na2", "")
+	expectStack(commandRunner, "b", "b", "b", "b", "b")
+	expectStack(commandRunner, "c", "c\nc2", "c\nc2\nc3", "", "")
+	commandRunner.Expect(jj.Abandon(jj.NewSelectedRevisions(&jj.Commit{ChangeId: "c"}, &jj.Commit{ChangeId: "c2"})))
+	defer commandRunner.Verify()
+
+	result, err := runSync(commandRunner)
+	assert.NoError(t, err)
+	assert.Equal(t, []stackResult{{root: "a", size: 2}}, result.moved)
+	assert.Equal(t, []stackResult{{root: "b", size: 1, conflicts: 1}}, result.conflict)
+	assert.Equal(t, []stackResult{{root: "c", size: 2}}, result.abandoned)
+	assert.Empty(t, result.failed)
+
+	summary := result.String()
+	assert.Contains(t, summary, "a (2 revisions)")
+	assert.Contains(t, summary, "b (1 of 1 revisions have conflicts)")
+	assert.Contains(t, summary, "Abandoned, landed upstream:\n  c (2 revisions)")
+}
+
+// a change created with `jj new -m "WIP"` is empty and described before the sync, it is not abandoned
+func Test_runSync_KeepsDescribedEmptyChange(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitFetch())
+	commandRunner.Expect(jj.GetIdsFromRevset(stackRootsRevset)).SetOutput([]byte("w\n"))
+	expectStack(commandRunner, "w", "", "w", "", "")
+	defer commandRunner.Verify()
+
+	result, err := runSync(commandRunner)
+	assert.NoError(t, err)
+	assert.Empty(t, result.abandoned)
+	assert.Equal(t, []stackResult{{root: "w", size: 1}}, result.moved)
+}
+
+func Test_runSync_NothingToDo(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitFetch())
+	commandRunner.Expect(jj.GetIdsFromRevset(stackRootsRevset))
+	defer commandRunner.Verify()
+
+	result, err := runSync(commandRunner)
+	assert.NoError(t, err)
+	assert.Contains(t, result.String(), "already up to date")
+}
+
+func Test_runSync_StopsWhenCancelled(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitFetch())
+	commandRunner.Expect(jj.GetIdsFromRevset(stackRootsRevset)).SetOutput([]byte("a\nb\n"))
+	commandRunner.Expect(jj.GetIdsFromRevset("a:: ~ empty() ~ working_copies()")).SetOutput([]byte("a"))
+	commandRunner.Expect(jj.Rebase(jj.NewSelectedRevisions(&jj.Commit{ChangeId: "a"}), "trunk()", "--source", "--destination")).SetError(common.ErrCommandCancelled)
+	defer commandRunner.Verify()
+
+	result, err := runSync(commandRunner)
+	assert.ErrorIs(t, err, common.ErrCommandCancelled)
+	assert.Len(t, result.failed, 1)
+}
@@ -93,7 +93,11 @@ func (h *Model) View() string {
 		h.printKeyBinding(h.keyMap.Cancel),
 		h.printKeyBinding(h.keyMap.Quit),
 		h.printKeyBinding(h.keyMap.Suspend),
+		h.printKeyBinding(h.keyMap.CancelCommand),
 		h.printKeyBinding(h.keyMap.Revset),
+		h.printKeyBinding(h.keyMap.CommandPalette),
+		h.printKeyBinding(h.keyMap.MacroRecord),
+		h.printKeyBinding(h.keyMap.MacroReplay),
 		h.printTitle("Exec"),
 		h.printKeyBinding(h.keyMap.ExecJJ),
 		h.printKeyBinding(h.keyMap.ExecShell),
@@ -103,6 +107,14 @@ func (h *Model) View() string {
 			h.keyMap.JumpToChildren.Help().Key,
 			h.keyMap.JumpToWorkingCopy.Help().Key,
 		), "jump to parent/child/working-copy"),
+		h.printKey(fmt.Sprintf("%s/%s",
+			h.keyMap.JumpToTop.Help().Key,
+			h.keyMap.JumpToBottom.Help().Key,
+		), "jump to top/bottom"),
+		h.printKey(fmt.Sprintf("%s/%s",
+			h.keyMap.HalfPageDown.Help().Key,
+			h.keyMap.HalfPageUp.Help().Key,
+		), "half page down/up"),
 		h.printKeyBinding(h.keyMap.ToggleSelect),
 		h.printKeyBinding(h.keyMap.AceJump),
 		h.printKeyBinding(h.keyMap.QuickSearch),
@@ -121,6 +133,7 @@ func (h *Model) View() string {
 		h.printKeyBinding(h.keyMap.Details.Mode),
 		h.printKeyBinding(h.keyMap.Bookmark.Set),
 		h.printKeyBinding(h.keyMap.InlineDescribe.Mode),
+		h.printKeyBinding(h.keyMap.MetaEdit.Mode),
 	)
 
 	var middle []string
@@ -155,6 +168,10 @@ func (h *Model) View() string {
 		h.printKeyBinding(h.keyMap.Duplicate.Onto),
 		h.printKeyBinding(h.keyMap.Duplicate.Before),
 		h.printKeyBinding(h.keyMap.Duplicate.After),
+		"",
+		h.printMode(h.keyMap.Run.Mode, "Run"),
+		h.printKeyBinding(h.keyMap.Run.Log),
+		h.printKeyBinding(h.keyMap.Run.Rerun),
 	)
 
 	var right []string
@@ -171,6 +188,9 @@ func (h *Model) View() string {
 		h.printMode(h.keyMap.Git.Mode, "Git"),
 		h.printKeyBinding(h.keyMap.Git.Push),
 		h.printKeyBinding(h.keyMap.Git.Fetch),
+		h.printKeyBinding(h.keyMap.Git.Remotes),
+		h.printKeyBinding(h.keyMap.Git.Sync),
+		h.printKeyBinding(h.keyMap.Submit.Mode),
 		"",
 		h.printMode(h.keyMap.Bookmark.Mode, "Bookmarks"),
 		h.printKeyBinding(h.keyMap.Bookmark.Move),
@@ -178,9 +198,30 @@ func (h *Model) View() string {
 		h.printKeyBinding(h.keyMap.Bookmark.Untrack),
 		h.printKeyBinding(h.keyMap.Bookmark.Track),
 		h.printKeyBinding(h.keyMap.Bookmark.Forget),
+		h.printKeyBinding(h.keyMap.Bookmark.Cleanup),
+		h.printKeyBinding(h.keyMap.Bookmark.Browse),
+		h.printKeyBinding(h.keyMap.Bookmark.Stack),
+		h.printMode(h.keyMap.Tag.Mode, "Tags"),
+		h.printKeyBinding(h.keyMap.Tag.Jump),
+		h.printKeyBinding(h.keyMap.Tag.Delete),
+		h.printKeyBinding(h.keyMap.Tag.Set),
+		h.printKeyBinding(h.keyMap.Tag.Revset),
+		h.printMode(h.keyMap.BookmarkBrowser.Mode, "Bookmark Browser"),
+		h.printKeyBinding(h.keyMap.BookmarkBrowser.Sort),
+		h.printKeyBinding(h.keyMap.BookmarkBrowser.Filter),
+		h.printKeyBinding(h.keyMap.BookmarkBrowser.Revset),
 		h.printMode(h.keyMap.OpLog.Mode, "Oplog"),
 		h.printKeyBinding(h.keyMap.Diff),
 		h.printKeyBinding(h.keyMap.OpLog.Restore),
+		h.printMode(h.keyMap.Divergence.Mode, "Divergent Changes"),
+		h.printKeyBinding(h.keyMap.Divergence.Abandon),
+		h.printKeyBinding(h.keyMap.Divergence.Squash),
+		h.printKeyBinding(h.keyMap.Divergence.NewChangeId),
+		h.printMode(h.keyMap.CommandLog.Mode, "Command Log"),
+		h.printKeyBinding(h.keyMap.CommandLog.Rerun),
+		h.printKeyBinding(h.keyMap.CommandLog.Copy),
+		h.printKeyBinding(h.keyMap.CommandLog.Output),
+		h.printKeyBinding(h.keyMap.CommandLog.All),
 		h.printMode(h.keyMap.Leader, "Leader"),
 		h.printMode(h.keyMap.CustomCommands, "Custom Commands"),
 	)
@@ -0,0 +1,211 @@
+package keysequence
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/ui/common"
+)
+
+// KeyMsg is a key resolved from a key sequence or a count prefix, it is handled as a key
+// pressed by the user without being matched against the key sequences again
+type KeyMsg tea.KeyMsg
+
+type timeoutMsg struct {
+	tag int
+}
+
+// Model collects the keys of key sequences such as "g g" and the counts typed before the
+// movement and selection keys such as "5j"
+type Model struct {
+	sequences  []config.KeySequence
+	repeatable []key.Binding
+	cancel     key.Binding
+	timeout    time.Duration
+	counts     bool
+	pending    []tea.KeyMsg
+	count      int
+	tag        int
+}
+
+func New() *Model {
+	keyMap := config.Current.GetKeyMap()
+	return &Model{
+		sequences: config.KeySequences(keyMap),
+		repeatable: []key.Binding{
+			keyMap.Up, keyMap.Down, keyMap.JumpToParent, keyMap.JumpToChildren, keyMap.ToggleSelect, keyMap.QuickSearchCycle,
+			keyMap.MacroReplay,
+		},
+		cancel:  keyMap.Cancel,
+		timeout: time.Duration(config.Current.KeySequence.Timeout) * time.Millisecond,
+		counts:  config.Current.KeySequence.Count,
+	}
+}
+
+// Pending reports whether a key sequence or a count is being typed
+func (m *Model) Pending() bool {
+	return len(m.pending) > 0 || m.count > 0
+}
+
+// String returns the keys typed so far
+func (m *Model) String() string {
+	var typed strings.Builder
+	if m.count > 0 {
+		typed.WriteString(strconv.Itoa(m.count))
+	}
+	for _, k := range m.pending {
+		typed.WriteString(k.String())
+	}
+	return typed.String()
+}
+
+// ShortHelp shows the keys which can follow the typed keys
+func (m *Model) ShortHelp() []key.Binding {
+	bindings := []key.Binding{m.cancel}
+	if len(m.pending) == 0 {
+		return append(bindings, m.repeatable...)
+	}
+	for _, sequence := range m.sequences {
+		if len(sequence.Keys) > len(m.pending) && m.hasPrefix(sequence.Keys, m.pending) {
+			rest := sequence.Keys[len(m.pending):]
+			bindings = append(bindings, key.NewBinding(key.WithKeys(rest[0]), key.WithHelp(config.JoinKeys(rest), sequence.Help)))
+		}
+	}
+	return bindings
+}
+
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+// Update handles the keys and the timeouts of the key sequences, it reports whether the message is
+// consumed. The consumed keys are sent back as KeyMsg once they are resolved.
+func (m *Model) Update(msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case timeoutMsg:
+		if msg.tag != m.tag || len(m.pending) == 0 {
+			return nil, true
+		}
+		if sequence, ok := m.match(m.pending); ok {
+			return m.resolveSequence(sequence), true
+		}
+		return tea.Sequence(m.flush()...), true
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return nil, false
+}
+
+func (m *Model) handleKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.Pending() && key.Matches(msg, m.cancel) {
+		m.reset()
+		return nil, true
+	}
+	typed := append(slices.Clone(m.pending), msg)
+	if m.isPrefix(typed) {
+		m.pending = typed
+		m.tag++
+		tag := m.tag
+		return common.Tick(m.timeout, func(time.Time) tea.Msg {
+			return timeoutMsg{tag: tag}
+		}), true
+	}
+	if sequence, ok := m.match(typed); ok {
+		return m.resolveSequence(sequence), true
+	}
+	if len(m.pending) > 0 {
+		// the typed keys are not a sequence, they are handled one by one
+		flushed := m.flush()
+		if m.isPrefix([]tea.KeyMsg{msg}) || m.isCount(msg) {
+			cmd, _ := m.handleKey(msg)
+			return tea.Sequence(append(flushed, cmd)...), true
+		}
+		return tea.Sequence(append(flushed, m.resolve(msg)...)...), true
+	}
+	if m.isCount(msg) {
+		m.count = m.count*10 + int(msg.Runes[0]-'0')
+		return nil, true
+	}
+	if m.count == 0 {
+		return nil, false
+	}
+	return tea.Sequence(m.resolve(msg)...), true
+}
+
+// isCount reports whether the key is a digit of a count, a count can't start with 0
+func (m *Model) isCount(msg tea.KeyMsg) bool {
+	if !m.counts || msg.Type != tea.KeyRunes || len(msg.Runes) != 1 || msg.Alt {
+		return false
+	}
+	digit := msg.Runes[0]
+	return digit >= '1' && digit <= '9' || digit == '0' && m.count > 0
+}
+
+// isPrefix reports whether the keys are the beginning of a longer key sequence
+func (m *Model) isPrefix(keys []tea.KeyMsg) bool {
+	return slices.ContainsFunc(m.sequences, func(sequence config.KeySequence) bool {
+		return len(sequence.Keys) > len(keys) && m.hasPrefix(sequence.Keys, keys)
+	})
+}
+
+func (m *Model) match(keys []tea.KeyMsg) (config.KeySequence, bool) {
+	idx := slices.IndexFunc(m.sequences, func(sequence config.KeySequence) bool {
+		return len(sequence.Keys) == len(keys) && m.hasPrefix(sequence.Keys, keys)
+	})
+	if idx == -1 {
+		return config.KeySequence{}, false
+	}
+	return m.sequences[idx], true
+}
+
+func (m *Model) hasPrefix(sequence []string, keys []tea.KeyMsg) bool {
+	for i, k := range keys {
+		if sequence[i] != k.String() {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Model) resolveSequence(sequence config.KeySequence) tea.Cmd {
+	m.pending = nil
+	return tea.Sequence(m.resolve(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(sequence.Key)})...)
+}
+
+// flush sends the typed keys one by one, the count applies to the first key
+func (m *Model) flush() []tea.Cmd {
+	pending := m.pending
+	m.pending = nil
+	var cmds []tea.Cmd
+	for _, k := range pending {
+		cmds = append(cmds, m.resolve(k)...)
+	}
+	return cmds
+}
+
+// resolve sends the key, it is repeated by the count when it is a movement or selection key
+func (m *Model) resolve(msg tea.KeyMsg) []tea.Cmd {
+	repeat := 1
+	if m.count > 0 && key.Matches(msg, m.repeatable...) {
+		repeat = m.count
+	}
+	m.count = 0
+	cmds := make([]tea.Cmd, repeat)
+	for i := range cmds {
+		cmds[i] = func() tea.Msg {
+			return KeyMsg(msg)
+		}
+	}
+	return cmds
+}
+
+func (m *Model) reset() {
+	m.pending = nil
+	m.count = 0
+	m.tag++
+}
@@ -0,0 +1,113 @@
+package keysequence
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder records the keys which reach the model after the key sequences and counts are resolved
+type recorder struct {
+	keys    *Model
+	pressed []string
+}
+
+func (r *recorder) Init() tea.Cmd {
+	return nil
+}
+
+func (r *recorder) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(KeyMsg); ok {
+		r.pressed = append(r.pressed, tea.KeyMsg(msg).String())
+		return r, nil
+	}
+	cmd, handled := r.keys.Update(msg)
+	if msg, ok := msg.(tea.KeyMsg); ok && !handled {
+		r.pressed = append(r.pressed, msg.String())
+	}
+	return r, cmd
+}
+
+func (r *recorder) View() string {
+	return ""
+}
+
+func newRecorder(t *testing.T) (*recorder, *test.Harness) {
+	original := config.Current.Keys.JumpToWorkingCopy
+	config.Current.Keys.JumpToWorkingCopy = []string{"@", "g g"}
+	t.Cleanup(func() { config.Current.Keys.JumpToWorkingCopy = original })
+
+	r := &recorder{keys: New()}
+	return r, test.NewHarness(t, r)
+}
+
+func TestKeySequence_Count(t *testing.T) {
+	r, h := newRecorder(t)
+	h.Type("12j")
+	assert.Len(t, r.pressed, 12)
+	assert.Equal(t, "j", r.pressed[0])
+	assert.False(t, r.keys.Pending())
+}
+
+func TestKeySequence_CountIsIgnoredByOtherKeys(t *testing.T) {
+	r, h := newRecorder(t)
+	h.Type("3d0")
+	assert.Equal(t, []string{"d", "0"}, r.pressed)
+}
+
+func TestKeySequence_Resolves(t *testing.T) {
+	r, h := newRecorder(t)
+	h.Type("g")
+	assert.True(t, r.keys.Pending())
+	assert.Equal(t, "g", r.keys.String())
+	assert.Empty(t, r.pressed)
+
+	h.Type("g")
+	assert.Equal(t, []string{"g g"}, r.pressed)
+	assert.False(t, r.keys.Pending())
+}
+
+func TestKeySequence_NotASequence(t *testing.T) {
+	r, h := newRecorder(t)
+	h.Type("2gj")
+	assert.Equal(t, []string{"g", "j"}, r.pressed)
+}
+
+func TestKeySequence_Timeout(t *testing.T) {
+	r, h := newRecorder(t)
+	h.Type("g")
+	h.Send(timeoutMsg{tag: r.keys.tag})
+	assert.Equal(t, []string{"g"}, r.pressed)
+	assert.False(t, r.keys.Pending())
+}
+
+func TestKeySequence_StaleTimeout(t *testing.T) {
+	r, h := newRecorder(t)
+	h.Type("g")
+	h.Send(timeoutMsg{tag: r.keys.tag - 1})
+	assert.Empty(t, r.pressed)
+	assert.True(t, r.keys.Pending())
+}
+
+func TestKeySequence_Cancel(t *testing.T) {
+	r, h := newRecorder(t)
+	h.Type("5g")
+	h.Press("esc")
+	assert.False(t, r.keys.Pending())
+	assert.Empty(t, r.pressed)
+
+	h.Press("esc")
+	assert.Equal(t, []string{"esc"}, r.pressed)
+}
+
+func TestKeySequence_Help(t *testing.T) {
+	r, h := newRecorder(t)
+	h.Type("g")
+	help := r.keys.ShortHelp()
+	assert.Len(t, help, 2)
+	assert.Equal(t, "g", help[1].Help().Key)
+	assert.Equal(t, "jump to working copy", help[1].Help().Desc)
+}
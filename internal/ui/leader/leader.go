@@ -3,6 +3,8 @@ package leader
 import (
 	"maps"
 	"slices"
+	"sort"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -93,6 +95,40 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 	return m, nil
 }
 
+// Entry is an action of the leader map, it is run by pressing its keys after the leader key
+type Entry struct {
+	Keys []string
+	Help string
+	Cmd  tea.Cmd
+}
+
+// Entries returns the actions of the leader map which are applicable to the selected item
+func Entries(ctx *context.MainContext) []Entry {
+	var entries []Entry
+	var collect func(keys []string, bnds context.LeaderMap)
+	collect = func(keys []string, bnds context.LeaderMap) {
+		for k, c := range contextEnabled(ctx, bnds) {
+			path := append(slices.Clone(keys), k)
+			if len(c.Nest) > 0 {
+				collect(path, c.Nest)
+			}
+			if len(c.Send) == 0 {
+				continue
+			}
+			help := c.Bind.Help().Desc
+			if help == "" {
+				help = strings.Join(c.Send, " ")
+			}
+			entries = append(entries, Entry{Keys: path, Help: help, Cmd: pendingCmd(sendCmds(c.Send))})
+		}
+	}
+	collect(nil, ctx.Leader)
+	sort.Slice(entries, func(i, j int) bool {
+		return strings.Join(entries[i].Keys, "") < strings.Join(entries[j].Keys, "")
+	})
+	return entries
+}
+
 func contextEnabled(ctx *context.MainContext, bnds context.LeaderMap) context.LeaderMap {
 	bnds = maps.Clone(bnds)
 	replacementKeys := slices.Collect(maps.Keys(ctx.CreateReplacements()))
@@ -241,3 +277,17 @@ func keysFromTypes() map[string]tea.Key {
 }
 
 var keyNames = keysFromTypes()
+
+// Key returns the key message of a key name such as "enter", "ctrl+r" or "alt+e", the other names
+// are sent as runes
+func Key(name string) tea.KeyMsg {
+	if k, ok := keyNames[name]; ok {
+		return tea.KeyMsg(k)
+	}
+	if rest, ok := strings.CutPrefix(name, "alt+"); ok && rest != "" {
+		k := Key(rest)
+		k.Alt = true
+		return k
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)}
+}
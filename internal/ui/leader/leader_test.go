@@ -1,6 +1,7 @@
 package leader
 
 import (
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -225,3 +226,50 @@ func TestTakePending_reduces_cmds_one_by_one(t *testing.T) {
 		t.Fatalf("expected nil command for empty send keys")
 	}
 }
+
+func TestEntries_lists_the_applicable_leaves(t *testing.T) {
+	content := `[leader.h]
+help = "Help"
+send = ["?"]
+
+[leader.g]
+help = "Git"
+
+[leader.gf]
+help = "Git Fetch"
+send = ["gf"]
+
+[leader.gp]
+context = [ "$change_id" ]
+send = ["gp"]
+`
+	lm, err := context.LoadLeader(content)
+	if err != nil {
+		t.Fatalf("LoadLeader failed: %v", err)
+	}
+	entries := Entries(&context.MainContext{Leader: lm})
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if strings.Join(entries[0].Keys, " ") != "g f" || entries[0].Help != "Git Fetch" {
+		t.Errorf("expected the git fetch entry, got %v %q", entries[0].Keys, entries[0].Help)
+	}
+	if strings.Join(entries[1].Keys, " ") != "h" || entries[1].Help != "Help" {
+		t.Errorf("expected the help entry, got %v %q", entries[1].Keys, entries[1].Help)
+	}
+	if _, ok := entries[1].Cmd().(PendingMsg); !ok {
+		t.Error("expected the entry to send its keys")
+	}
+}
+
+func TestKey_detects_names_and_alt(t *testing.T) {
+	if k := Key("ctrl+r"); k.Type != tea.KeyCtrlR {
+		t.Errorf("expected KeyCtrlR, got %v", k.Type)
+	}
+	if k := Key("alt+e"); k.String() != "alt+e" {
+		t.Errorf("expected alt+e, got %q", k.String())
+	}
+	if k := Key("x"); k.Type != tea.KeyRunes || string(k.Runes) != "x" {
+		t.Errorf("expected rune 'x', got type %v runes %q", k.Type, string(k.Runes))
+	}
+}
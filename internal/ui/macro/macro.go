@@ -0,0 +1,271 @@
+package macro
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/leader"
+)
+
+// KeyMsg is a key replayed from a macro, it is handled as a key pressed by the user without being
+// recorded
+type KeyMsg tea.KeyMsg
+
+type stepMsg struct {
+	tag int
+}
+
+// landed is queued after the refresh which moves the cursor to a checked revision, the keys of the
+// revision are only sent when the cursor is on it
+type landed string
+
+// stepInterval is how often the playback checks whether the previous key is done
+const stepInterval = 20 * time.Millisecond
+
+type prompt int
+
+const (
+	noPrompt prompt = iota
+	recordPrompt
+	replayPrompt
+)
+
+// Model records the keys pressed into registers named by a single character, and replays them
+// once the commands started by the previous key are completed. The registers are kept until jjui
+// exits, a macro is kept across sessions by adding it to the custom commands as `macro = [...]`.
+type Model struct {
+	context   *context.MainContext
+	keyMap    config.KeyMappings[key.Binding]
+	loading   func() bool
+	registers map[string][]tea.KeyMsg
+	prompt    prompt
+	times     int
+	recording string
+	recorded  []tea.KeyMsg
+	queue     []tea.Msg
+	tag       int
+}
+
+// New creates the macros, loading reports whether the revisions are being loaded, the playback
+// waits for it like it waits for the running commands
+func New(ctx *context.MainContext, loading func() bool) *Model {
+	return &Model{
+		context:   ctx,
+		keyMap:    config.Current.GetKeyMap(),
+		loading:   loading,
+		registers: make(map[string][]tea.KeyMsg),
+	}
+}
+
+// Pending reports whether the register of a macro is being asked
+func (m *Model) Pending() bool {
+	return m.prompt != noPrompt
+}
+
+// Playing reports whether a macro is being replayed
+func (m *Model) Playing() bool {
+	return len(m.queue) > 0
+}
+
+// Recording returns the register the keys are recorded to, it is empty when nothing is recorded
+func (m *Model) Recording() string {
+	return m.recording
+}
+
+// String returns the mode shown in the status bar
+func (m *Model) String() string {
+	switch {
+	case m.prompt == recordPrompt:
+		return "record"
+	case m.prompt == replayPrompt && m.times > 1:
+		return fmt.Sprintf("replay %d", m.times)
+	case m.prompt == replayPrompt:
+		return "replay"
+	case m.recording != "":
+		return "rec @" + m.recording
+	}
+	return ""
+}
+
+func (m *Model) ShortHelp() []key.Binding {
+	return []key.Binding{
+		m.keyMap.Cancel,
+		key.NewBinding(key.WithKeys("a"), key.WithHelp("a-z", "register")),
+	}
+}
+
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+// Record adds the key to the macro being recorded
+func (m *Model) Record(msg tea.KeyMsg) {
+	if m.recording != "" {
+		m.recorded = append(m.recorded, msg)
+	}
+}
+
+// Update handles the keys which record and replay the macros, and the playback of the macros. It
+// reports whether the message is consumed.
+func (m *Model) Update(msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case stepMsg:
+		if msg.tag != m.tag {
+			return nil, true
+		}
+		return m.step(), true
+	case common.PlayMacroMsg:
+		keys := make([]tea.KeyMsg, len(msg.Keys))
+		for i, k := range msg.Keys {
+			if k == "space" {
+				k = " "
+			}
+			keys[i] = leader.Key(k)
+		}
+		return m.play(keys, 1), true
+	case common.CommandCompletedMsg:
+		// the rest of the macro would run against an unexpected state
+		if msg.Err != nil && m.Playing() {
+			m.stop()
+		}
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return nil, false
+}
+
+func (m *Model) handleKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.Pending() {
+		prompt := m.prompt
+		m.prompt = noPrompt
+		switch {
+		case key.Matches(msg, m.keyMap.Cancel):
+			return nil, true
+		case prompt == replayPrompt && key.Matches(msg, m.keyMap.MacroReplay):
+			// a count before the replay key sends it again for every repeat
+			m.prompt = replayPrompt
+			m.times++
+			return nil, true
+		case !isRegister(msg):
+			return flash(fmt.Sprintf("%s is not a register, the registers are a-z", msg.String()), true), true
+		case prompt == recordPrompt:
+			m.recording = msg.String()
+			m.recorded = nil
+			return nil, true
+		}
+		keys := m.registers[msg.String()]
+		if len(keys) == 0 {
+			return flash(fmt.Sprintf("macro @%s is empty", msg.String()), true), true
+		}
+		return m.play(keys, m.times), true
+	}
+
+	switch {
+	case m.Playing() && key.Matches(msg, m.keyMap.Cancel):
+		m.stop()
+		return flash("macro is stopped", false), true
+	case key.Matches(msg, m.keyMap.MacroRecord) && m.recording != "":
+		register, keys := m.recording, m.recorded
+		m.recording = ""
+		m.recorded = nil
+		m.registers[register] = keys
+		return flash(fmt.Sprintf("macro @%s is recorded, keep it as a custom command with macro = %s", register, Keys(keys)), false), true
+	case key.Matches(msg, m.keyMap.MacroRecord) && !m.Playing():
+		m.prompt = recordPrompt
+		return nil, true
+	case key.Matches(msg, m.keyMap.MacroReplay) && !m.Playing():
+		m.prompt = replayPrompt
+		m.times = 1
+		return nil, true
+	}
+	return nil, false
+}
+
+// play replays the keys the given times, a macro replayed once is replayed for each checked
+// revision with the cursor moved to the revision
+func (m *Model) play(keys []tea.KeyMsg, times int) tea.Cmd {
+	var queue []tea.Msg
+	var revisions []string
+	if times == 1 {
+		for _, item := range m.context.CheckedItems {
+			if revision, ok := item.(context.SelectedRevision); ok {
+				revisions = append(revisions, revision.ChangeId)
+			}
+		}
+	}
+	if len(revisions) > 0 {
+		for _, revision := range revisions {
+			queue = append(queue, common.RefreshMsg{SelectedRevision: revision}, landed(revision))
+			for _, k := range keys {
+				queue = append(queue, KeyMsg(k))
+			}
+		}
+	} else {
+		for range times {
+			for _, k := range keys {
+				queue = append(queue, KeyMsg(k))
+			}
+		}
+	}
+	m.queue = append(m.queue, queue...)
+	m.tag++
+	return m.step()
+}
+
+// step sends the next message of the macro when the commands started by the previous one are done,
+// the interactive commands are waited for too as they run as jobs until their process exits
+func (m *Model) step() tea.Cmd {
+	if len(m.queue) == 0 {
+		return nil
+	}
+	tag := m.tag
+	next := func(time.Time) tea.Msg {
+		return stepMsg{tag: tag}
+	}
+	if running, queued := m.context.Jobs.Counts(); running > 0 || queued > 0 || m.loading() {
+		return common.Tick(stepInterval, next)
+	}
+	msg := m.queue[0]
+	m.queue = m.queue[1:]
+	if revision, ok := msg.(landed); ok {
+		// the revision may be gone, e.g. abandoned by an earlier pass, the cursor is on @ then
+		if selected, ok := m.context.SelectedItem.(context.SelectedRevision); !ok || selected.ChangeId != string(revision) {
+			m.stop()
+			return flash(fmt.Sprintf("macro is stopped, %s is not in the revisions anymore", revision), true)
+		}
+		return m.step()
+	}
+	return tea.Sequence(func() tea.Msg { return msg }, common.Tick(stepInterval, next))
+}
+
+func (m *Model) stop() {
+	m.queue = nil
+	m.tag++
+}
+
+// Keys returns the keys as they are configured, e.g. ["D", "enter"]
+func Keys(keys []tea.KeyMsg) string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = fmt.Sprintf("%q", k.String())
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
+func isRegister(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyRunes && !msg.Alt && len(msg.Runes) == 1 &&
+		slices.Contains([]rune("abcdefghijklmnopqrstuvwxyz"), msg.Runes[0])
+}
+
+func flash(text string, isError bool) tea.Cmd {
+	return func() tea.Msg {
+		return common.FlashMsg{Text: text, Error: isError}
+	}
+}
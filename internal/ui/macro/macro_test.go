@@ -0,0 +1,206 @@
+package macro
+
+import (
+	stdcontext "context"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder records the keys which reach the model, and the revisions the playback moves to
+type recorder struct {
+	macro   *Model
+	pressed []string
+	// run returns the commands started by the replayed keys
+	run map[string]tea.Cmd
+	// gone are the revisions the refresh can't move the cursor to
+	gone map[string]bool
+}
+
+// goneMsg removes a revision from the view, like abandoning it
+type goneMsg string
+
+func (r *recorder) Init() tea.Cmd {
+	return nil
+}
+
+func (r *recorder) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case KeyMsg:
+		r.pressed = append(r.pressed, tea.KeyMsg(msg).String())
+		return r, r.run[tea.KeyMsg(msg).String()]
+	case common.RefreshMsg:
+		r.pressed = append(r.pressed, "refresh "+msg.SelectedRevision)
+		selected := context.SelectedRevision{ChangeId: msg.SelectedRevision}
+		if r.gone[msg.SelectedRevision] {
+			selected = context.SelectedRevision{ChangeId: "@"}
+		}
+		r.macro.context.SelectedItem = selected
+		return r, nil
+	case goneMsg:
+		r.gone[string(msg)] = true
+		return r, nil
+	}
+	cmd, handled := r.macro.Update(msg)
+	if msg, ok := msg.(tea.KeyMsg); ok && !handled {
+		r.macro.Record(msg)
+		r.pressed = append(r.pressed, msg.String())
+	}
+	return r, cmd
+}
+
+func (r *recorder) View() string {
+	return ""
+}
+
+func newRecorder(t *testing.T) (*recorder, *test.Harness) {
+	ctx := &context.MainContext{Jobs: context.NewJobs()}
+	r := &recorder{macro: New(ctx, func() bool { return false }), gone: make(map[string]bool)}
+	return r, test.NewHarness(t, r)
+}
+
+func TestMacro_RecordAndReplay(t *testing.T) {
+	r, h := newRecorder(t)
+	h.Type("Qa")
+	assert.Equal(t, "rec @a", r.macro.String())
+	h.Type("jD")
+	h.Press("enter")
+	h.Type("Q")
+	assert.Empty(t, r.macro.Recording())
+	assert.Equal(t, []string{"j", "D", "enter"}, r.pressed)
+
+	r.pressed = nil
+	h.Type(".a")
+	h.Advance(time.Second)
+	assert.Equal(t, []string{"j", "D", "enter"}, r.pressed)
+	assert.False(t, r.macro.Playing())
+}
+
+func TestMacro_ReplayTimes(t *testing.T) {
+	r, h := newRecorder(t)
+	h.Type("QajQ")
+	r.pressed = nil
+
+	// a count sends the replay key for every repeat
+	h.Type("...")
+	assert.Equal(t, "replay 3", r.macro.String())
+	h.Type("a")
+	h.Advance(time.Second)
+	assert.Equal(t, []string{"j", "j", "j"}, r.pressed)
+}
+
+func TestMacro_ReplayForEachCheckedRevision(t *testing.T) {
+	r, h := newRecorder(t)
+	h.Type("QaDQ")
+	r.pressed = nil
+
+	r.macro.context.CheckedItems = []context.SelectedItem{
+		context.SelectedRevision{ChangeId: "abc"},
+		context.SelectedRevision{ChangeId: "def"},
+	}
+	h.Type(".a")
+	h.Advance(time.Second)
+	assert.Equal(t, []string{"refresh abc", "D", "refresh def", "D"}, r.pressed)
+}
+
+func TestMacro_StopsWhenACheckedRevisionIsGone(t *testing.T) {
+	r, h := newRecorder(t)
+	h.Type("QaaQ")
+	r.pressed = nil
+
+	r.macro.context.CheckedItems = []context.SelectedItem{
+		context.SelectedRevision{ChangeId: "abc"},
+		context.SelectedRevision{ChangeId: "def"},
+	}
+	// the first pass abandons the second checked revision
+	r.run = map[string]tea.Cmd{"a": func() tea.Msg { return goneMsg("def") }}
+	h.Type(".a")
+	h.Advance(time.Second)
+	assert.Equal(t, []string{"refresh abc", "a", "refresh def"}, r.pressed)
+	assert.False(t, r.macro.Playing())
+}
+
+func TestMacro_EmptyRegister(t *testing.T) {
+	r, h := newRecorder(t)
+	r.macro.Update(test.Key("."))
+	cmd, _ := r.macro.Update(test.Key("b"))
+	assert.Equal(t, common.FlashMsg{Text: "macro @b is empty", Error: true}, cmd())
+	assert.False(t, r.macro.Playing())
+	h.Type("j")
+	assert.Equal(t, []string{"j"}, r.pressed)
+}
+
+func TestMacro_NotARegister(t *testing.T) {
+	r, h := newRecorder(t)
+	h.Type("Q1")
+	assert.False(t, r.macro.Pending())
+	assert.Empty(t, r.macro.Recording())
+}
+
+func TestMacro_CancelPrompt(t *testing.T) {
+	r, h := newRecorder(t)
+	h.Type("Q")
+	assert.True(t, r.macro.Pending())
+	h.Press("esc")
+	assert.False(t, r.macro.Pending())
+	assert.Empty(t, r.pressed)
+}
+
+func TestMacro_PlayNamedMacro(t *testing.T) {
+	r, h := newRecorder(t)
+	h.Send(common.PlayMacroMsg{Keys: []string{"D", "space", "ctrl+s", "alt+j"}})
+	h.Advance(time.Second)
+	assert.Equal(t, []string{"D", " ", "ctrl+s", "alt+j"}, r.pressed)
+}
+
+func TestMacro_WaitsForLoading(t *testing.T) {
+	r, h := newRecorder(t)
+	loading := true
+	r.macro.loading = func() bool { return loading }
+	h.Send(common.PlayMacroMsg{Keys: []string{"j"}})
+	h.Advance(time.Second)
+	assert.Empty(t, r.pressed)
+	assert.True(t, r.macro.Playing())
+
+	loading = false
+	h.Advance(stepInterval)
+	assert.Equal(t, []string{"j"}, r.pressed)
+}
+
+func TestMacro_WaitsForInteractiveCommands(t *testing.T) {
+	r, h := newRecorder(t)
+	// an interactive command holds its job until its process exits
+	var exit func()
+	r.run = map[string]tea.Cmd{"D": func() tea.Msg {
+		exit, _ = r.macro.context.Jobs.Start(stdcontext.Background(), false)
+		return nil
+	}}
+	h.Send(common.PlayMacroMsg{Keys: []string{"D", "j"}})
+	h.Advance(time.Second)
+	assert.Equal(t, []string{"D"}, r.pressed)
+	assert.True(t, r.macro.Playing())
+
+	exit()
+	h.Advance(stepInterval)
+	assert.Equal(t, []string{"D", "j"}, r.pressed)
+}
+
+func TestMacro_StopsOnError(t *testing.T) {
+	r, _ := newRecorder(t)
+	r.macro.loading = func() bool { return true }
+	r.macro.Update(common.PlayMacroMsg{Keys: []string{"j"}})
+	assert.True(t, r.macro.Playing())
+	_, handled := r.macro.Update(common.CommandCompletedMsg{Err: assert.AnError})
+	assert.False(t, handled)
+	assert.False(t, r.macro.Playing())
+}
+
+func TestKeys(t *testing.T) {
+	keys := []tea.KeyMsg{test.Key("D"), test.Key("enter"), {Type: tea.KeySpace, Runes: []rune{' '}}}
+	assert.Equal(t, `["D", "enter", " "]`, Keys(keys))
+}
@@ -0,0 +1,216 @@
+package metaedit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+)
+
+type field int
+
+// defines the order of fields in the form
+const (
+	authorName field = iota
+	authorEmail
+	resetAuthor
+	updateAuthorTimestamp
+	updateChangeId
+	fieldCount
+)
+
+var toggleLabels = map[field]string{
+	resetAuthor:           "Reset author to the configured user",
+	updateAuthorTimestamp: "Update author timestamp",
+	updateChangeId:        "Generate a new change id",
+}
+
+type Model struct {
+	context       *context.MainContext
+	keyMap        config.KeyMappings[key.Binding]
+	revisions     jj.SelectedRevisions
+	name          textinput.Model
+	email         textinput.Model
+	originalName  string
+	originalEmail string
+	toggles       map[field]bool
+	focused       field
+	err           string
+	styles        common.DialogStyles
+}
+
+func (m *Model) ShortHelp() []key.Binding {
+	return []key.Binding{m.keyMap.MetaEdit.Next, m.keyMap.MetaEdit.Prev, m.keyMap.MetaEdit.Toggle, m.keyMap.Apply, m.keyMap.Cancel}
+}
+
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keyMap.Cancel):
+			return m, common.Close
+		case key.Matches(msg, m.keyMap.Apply):
+			if err := m.validate(); err != "" {
+				m.err = err
+				return m, nil
+			}
+			flags := m.flags()
+			if len(flags) == 0 {
+				return m, common.Close
+			}
+			return m, m.context.RunCommand(jj.MetaEdit(m.revisions, flags...), common.Refresh, common.Close)
+		case key.Matches(msg, m.keyMap.MetaEdit.Next):
+			return m, m.focus((m.focused + 1) % fieldCount)
+		case key.Matches(msg, m.keyMap.MetaEdit.Prev):
+			return m, m.focus((m.focused + fieldCount - 1) % fieldCount)
+		case key.Matches(msg, m.keyMap.MetaEdit.Toggle) && m.focused >= resetAuthor:
+			m.toggles[m.focused] = !m.toggles[m.focused]
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.focused {
+	case authorName:
+		m.name, cmd = m.name.Update(msg)
+	case authorEmail:
+		m.email, cmd = m.email.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m *Model) focus(f field) tea.Cmd {
+	m.focused = f
+	m.name.Blur()
+	m.email.Blur()
+	switch f {
+	case authorName:
+		return m.name.Focus()
+	case authorEmail:
+		return m.email.Focus()
+	}
+	return nil
+}
+
+// validate returns the reason the author can't be changed, jj would accept an author without a
+// name or an email
+func (m *Model) validate() string {
+	if m.toggles[resetAuthor] {
+		return ""
+	}
+	name := strings.TrimSpace(m.name.Value())
+	email := strings.TrimSpace(m.email.Value())
+	if name == m.originalName && email == m.originalEmail {
+		return ""
+	}
+	if name == "" || email == "" {
+		return "author name and email can't be empty"
+	}
+	return ""
+}
+
+// flags maps each changed field to its `jj metaedit` flag
+func (m *Model) flags() []string {
+	var flags []string
+	name := strings.TrimSpace(m.name.Value())
+	email := strings.TrimSpace(m.email.Value())
+	if m.toggles[resetAuthor] {
+		flags = append(flags, "--update-author")
+	} else if name != m.originalName || email != m.originalEmail {
+		flags = append(flags, "--author", fmt.Sprintf("%s <%s>", name, email))
+	}
+	if m.toggles[updateAuthorTimestamp] {
+		flags = append(flags, "--update-author-timestamp")
+	}
+	if m.toggles[updateChangeId] {
+		flags = append(flags, "--update-change-id")
+	}
+	return flags
+}
+
+func (m *Model) View() string {
+	var lines []string
+	title := "Edit metadata of " + strings.Join(m.revisions.GetIds(), ", ")
+	lines = append(lines, m.styles.Title.Render(title), "")
+	lines = append(lines, m.renderInput(authorName, "Author name ", m.name))
+	lines = append(lines, m.renderInput(authorEmail, "Author email", m.email))
+	if m.err != "" {
+		lines = append(lines, m.styles.Error.Render(m.err))
+	}
+	lines = append(lines, "")
+	for f := resetAuthor; f < fieldCount; f++ {
+		mark := "[ ]"
+		if m.toggles[f] {
+			mark = "[x]"
+		}
+		style := m.styles.Text
+		if m.focused == f {
+			style = m.styles.Selected
+		}
+		lines = append(lines, style.Render(fmt.Sprintf("%s %s", mark, toggleLabels[f])))
+	}
+	if m.toggles[resetAuthor] {
+		lines = append(lines, "", m.styles.Dimmed.Render("author name and email are ignored when resetting the author"))
+	}
+	lines = append(lines, "", m.styles.RenderHelp(m.ShortHelp()))
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	width, height := lipgloss.Size(content)
+	content = lipgloss.Place(width, height, 0, 0, content, lipgloss.WithWhitespaceBackground(m.styles.Text.GetBackground()))
+	return m.styles.Border.Render(content)
+}
+
+func (m *Model) renderInput(f field, label string, input textinput.Model) string {
+	labelStyle := m.styles.Dimmed
+	if m.focused == f {
+		labelStyle = m.styles.Selected
+	}
+	return lipgloss.JoinHorizontal(0, labelStyle.Render(label), m.styles.Text.Render(" "), input.View())
+}
+
+func newInput(value string, textStyle lipgloss.Style) textinput.Model {
+	t := textinput.New()
+	t.Prompt = ""
+	t.Width = 40
+	t.CharLimit = 120
+	t.TextStyle = textStyle
+	t.Cursor.TextStyle = textStyle
+	t.SetValue(value)
+	return t
+}
+
+func NewModel(ctx *context.MainContext, revisions jj.SelectedRevisions) *Model {
+	s := common.NewDialogStyles("metaedit")
+
+	var name, email string
+	if output, err := ctx.RunCommandImmediate(jj.GetAuthor(revisions.Last())); err == nil {
+		name, email, _ = strings.Cut(string(output), "\n")
+	}
+
+	m := &Model{
+		context:       ctx,
+		keyMap:        config.Current.GetKeyMap(),
+		revisions:     revisions,
+		name:          newInput(name, s.Text),
+		email:         newInput(email, s.Text),
+		originalName:  name,
+		originalEmail: email,
+		toggles:       make(map[field]bool),
+		styles:        s,
+	}
+	m.focus(authorName)
+	return m
+}
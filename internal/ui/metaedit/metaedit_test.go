@@ -0,0 +1,74 @@
+package metaedit
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var revisions = jj.NewSelectedRevisions(&jj.Commit{ChangeId: "abc"})
+
+func Test_ChangeAuthor(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetAuthor("abc")).SetOutput([]byte("Jane Doe\njane@example.com"))
+	commandRunner.Expect(jj.MetaEdit(revisions, "--author", "Jane Roe <jane@example.com>"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), revisions)
+	tm := teatest.NewTestModel(t, test.NewShell(model))
+	for range "Doe" {
+		tm.Send(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	tm.Type("Roe")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
+func Test_ResetAuthorAndChangeId(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetAuthor("abc")).SetOutput([]byte("Jane Doe\njane@example.com"))
+	commandRunner.Expect(jj.MetaEdit(revisions, "--update-author", "--update-change-id"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), revisions)
+	tm := teatest.NewTestModel(t, test.NewShell(model))
+	tm.Send(tea.KeyMsg{Type: tea.KeyTab})
+	tm.Send(tea.KeyMsg{Type: tea.KeyTab})
+	tm.Type(" ")
+	tm.Send(tea.KeyMsg{Type: tea.KeyTab})
+	tm.Send(tea.KeyMsg{Type: tea.KeyTab})
+	tm.Type(" ")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
+func Test_EmptyEmailIsRejected(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetAuthor("abc")).SetOutput([]byte("Jane Doe\njane@example.com"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), revisions)
+	model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	for range "jane@example.com" {
+		model.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, cmd)
+	assert.Contains(t, model.View(), "author name and email can't be empty")
+}
+
+func Test_NoChangesCloses(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetAuthor("abc")).SetOutput([]byte("Jane Doe\njane@example.com"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), revisions)
+	tm := teatest.NewTestModel(t, test.NewShell(model))
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
@@ -1,13 +1,27 @@
 package operations
 
 import (
+	"strings"
+
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
 )
 
+type defaultStyles struct {
+	review  lipgloss.Style
+	success lipgloss.Style
+	failure lipgloss.Style
+	pending lipgloss.Style
+}
+
 type Default struct {
-	keyMap config.KeyMappings[key.Binding]
+	context *context.MainContext
+	keyMap  config.KeyMappings[key.Binding]
+	styles  defaultStyles
 }
 
 func (n *Default) ShortHelp() []key.Binding {
@@ -33,16 +47,51 @@ func (n *Default) FullHelp() [][]key.Binding {
 	return [][]key.Binding{n.ShortHelp()}
 }
 
-func (n *Default) Render(*jj.Commit, RenderPosition) string {
-	return ""
+// Render shows the id of the code review submitted for the revision and the badges of its
+// CI/review statuses
+func (n *Default) Render(commit *jj.Commit, pos RenderPosition) string {
+	if pos != RenderBeforeChangeId || n.context == nil || commit == nil {
+		return ""
+	}
+	var badges []string
+	if review := n.context.Reviews.ForCommit(commit.CommitId); review != "" {
+		badges = append(badges, n.styles.review.Render(review))
+	}
+	for _, status := range n.context.Statuses.Get(commit.CommitId) {
+		badges = append(badges, n.badge(status))
+	}
+	return strings.Join(badges, " ")
+}
+
+func (n *Default) badge(status context.CommitStatus) string {
+	label := status.Label
+	if label == "" {
+		label = status.State
+	}
+	switch status.State {
+	case context.StatusSuccess:
+		return n.styles.success.Render("✓" + label)
+	case context.StatusFailure:
+		return n.styles.failure.Render("✗" + label)
+	case context.StatusPending:
+		return n.styles.pending.Render("●" + label)
+	}
+	return n.styles.review.Render(label)
 }
 
 func (n *Default) Name() string {
 	return "normal"
 }
 
-func NewDefault() *Default {
+func NewDefault(c *context.MainContext) *Default {
 	return &Default{
-		keyMap: config.Current.GetKeyMap(),
+		context: c,
+		keyMap:  config.Current.GetKeyMap(),
+		styles: defaultStyles{
+			review:  common.DefaultPalette.Get("revisions dimmed"),
+			success: common.DefaultPalette.Get("revisions success"),
+			failure: common.DefaultPalette.Get("revisions error"),
+			pending: common.DefaultPalette.Get("revisions dimmed"),
+		},
 	}
 }
@@ -0,0 +1,25 @@
+package operations
+
+import (
+	"testing"
+
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefault_RendersStatusBadges(t *testing.T) {
+	c := &context.MainContext{Statuses: context.NewStatuses()}
+	c.Statuses.Set([]context.CommitStatus{
+		{CommitId: "abc123", State: context.StatusSuccess, Label: "ci"},
+		{CommitId: "abc123", State: context.StatusPending, Label: "review"},
+	})
+	op := NewDefault(c)
+
+	commit := &jj.Commit{ChangeId: "kmtnvwpx", CommitId: "abc1"}
+	rendered := op.Render(commit, RenderBeforeChangeId)
+	assert.Contains(t, rendered, "✓ci")
+	assert.Contains(t, rendered, "●review")
+	assert.Empty(t, op.Render(commit, RenderBeforeCommitId))
+	assert.Empty(t, op.Render(&jj.Commit{ChangeId: "zqrsxlyo", CommitId: "def4"}, RenderBeforeChangeId))
+}
@@ -137,6 +137,8 @@ type Model struct {
 	context      *context.MainContext
 	keyMap       config.KeyMappings[key.Binding]
 	styles       styles
+	// fileToSelect is the file to select once the files are loaded
+	fileToSelect string
 }
 
 type updateCommitStatusMsg struct {
@@ -201,7 +203,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return common.ShowDiffMsg(output)
 			}
 		case key.Matches(msg, m.keyMap.Details.Edit):
-			editRevision := func() tea.Msg { return common.EditRevision(m.revision) }
+			editRevision := func() tea.Msg { return common.EditRevision(m.revision.GetChangeId()) }
 			selectedFiles, _ := m.getSelectedFiles()
 			editFiles := func() tea.Msg {
 				line := []string{config.GetDefaultEditor()}
@@ -313,25 +315,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		items := m.createListItems(msg.summary, msg.selectedFiles)
 		var selectionChangedCmd tea.Cmd
 		m.context.ClearCheckedItems(reflect.TypeFor[context.SelectedFile]())
+		selectedIndex := 0
 		if len(items) > 0 {
-			var first context.SelectedItem
-			for _, it := range items {
+			var selected context.SelectedItem
+			for i, it := range items {
 				it := it.(item)
 				sel := context.SelectedFile{
 					ChangeId: m.revision.GetChangeId(),
 					CommitId: m.revision.CommitId,
 					File:     it.fileName,
 				}
-				if first == nil {
-					first = sel
+				if selected == nil || it.fileName == m.fileToSelect {
+					selected = sel
+					selectedIndex = i
 				}
 				if it.selected {
 					m.context.AddCheckedItem(sel)
 				}
 			}
-			selectionChangedCmd = m.context.SetSelectedItem(first)
+			selectionChangedCmd = m.context.SetSelectedItem(selected)
 		}
-		return m, tea.Batch(selectionChangedCmd, m.files.SetItems(items))
+		cmd := m.files.SetItems(items)
+		if m.fileToSelect != "" {
+			m.files.Select(selectedIndex)
+			m.fileToSelect = ""
+		}
+		return m, tea.Batch(selectionChangedCmd, cmd)
 	case tea.WindowSizeMsg:
 		m.height = msg.Height
 	}
@@ -441,12 +450,14 @@ func (m Model) View() string {
 	return lipgloss.Place(w, h, 0, 0, view, lipgloss.WithWhitespaceBackground(m.styles.Text.GetBackground()))
 }
 
+// load snapshots the working copy before listing the files, the snapshot waits for the commands
+// changing the repo so it runs in the command
 func (m Model) load(revision string) tea.Cmd {
-	output, err := m.context.RunCommandImmediate(jj.Snapshot())
-	if err == nil {
-		output, err = m.context.RunCommandImmediate(jj.Status(revision))
+	return func() tea.Msg {
+		output, err := m.context.RunCommandImmediate(jj.Snapshot())
 		if err == nil {
-			return func() tea.Msg {
+			output, err = m.context.RunCommandImmediate(jj.Status(revision))
+			if err == nil {
 				summary := string(output)
 				selectedFiles, isVirtuallySelected := m.getSelectedFiles()
 				if isVirtuallySelected {
@@ -455,8 +466,6 @@ func (m Model) load(revision string) tea.Cmd {
 				return updateCommitStatusMsg{summary, selectedFiles}
 			}
 		}
-	}
-	return func() tea.Msg {
 		return common.CommandCompletedMsg{
 			Output: string(output),
 			Err:    err,
@@ -89,3 +89,21 @@ func TestModel_Update_HandlesMovedFiles(t *testing.T) {
 	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
 	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
 }
+
+func TestNewOperationWithFile_SelectsFile(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.Restore(Revision, []string{"newfile.txt"}))
+	defer commandRunner.Verify()
+
+	op, _ := NewOperationWithFile(test.NewTestContext(commandRunner), Commit, "newfile.txt")
+	tm := teatest.NewTestModel(t, test.NewShell(op.(*Operation).Overlay))
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("newfile.txt"))
+	})
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
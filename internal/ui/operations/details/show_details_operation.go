@@ -62,3 +62,14 @@ func NewOperation(context *context.MainContext, selected *jj.Commit) (operations
 	}
 	return op, op.Overlay.Init()
 }
+
+// NewOperationWithFile opens the details of the revision with the file selected
+func NewOperationWithFile(context *context.MainContext, selected *jj.Commit, file string) (operations.Operation, tea.Cmd) {
+	model := New(context, selected).(Model)
+	model.fileToSelect = file
+	op := &Operation{
+		Overlay: model,
+		keyMap:  config.Current.GetKeyMap(),
+	}
+	return op, op.Overlay.Init()
+}
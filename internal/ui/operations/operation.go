@@ -33,3 +33,9 @@ type TracksSelectedRevision interface {
 type HandleKey interface {
 	HandleKey(msg tea.KeyMsg) tea.Cmd
 }
+
+// HandleMsg is implemented by operations that need to receive non-key messages
+// (e.g. results of background work) while leaving navigation to the revisions view.
+type HandleMsg interface {
+	HandleMsg(msg tea.Msg) tea.Cmd
+}
@@ -0,0 +1,314 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	appContext "github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/operations"
+)
+
+type status int
+
+const (
+	queued status = iota
+	running
+	passed
+	failed
+)
+
+type result struct {
+	revision string
+	status   status
+	output   string
+	duration time.Duration
+}
+
+type resultMsg struct {
+	index    int
+	output   string
+	err      error
+	duration time.Duration
+}
+
+type Operation struct {
+	context *appContext.MainContext
+	keyMap  config.KeyMappings[key.Binding]
+	command string
+	results []*result
+	current *jj.Commit
+	ctx     context.Context
+	cancel  context.CancelFunc
+	styles  styles
+}
+
+type styles struct {
+	dimmed   lipgloss.Style
+	running  lipgloss.Style
+	success  lipgloss.Style
+	error    lipgloss.Style
+	changeId lipgloss.Style
+}
+
+func (o *Operation) SetSelectedRevision(commit *jj.Commit) {
+	o.current = commit
+}
+
+func (o *Operation) ShortHelp() []key.Binding {
+	return []key.Binding{o.keyMap.Up, o.keyMap.Down, o.keyMap.Cancel, o.keyMap.Run.Log, o.keyMap.Run.Rerun}
+}
+
+func (o *Operation) FullHelp() [][]key.Binding {
+	return [][]key.Binding{o.ShortHelp()}
+}
+
+func (o *Operation) HandleKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, o.keyMap.Cancel):
+		o.cancel()
+		return common.Close
+	case key.Matches(msg, o.keyMap.Run.Log):
+		if r := o.resultOf(o.current); r != nil && (r.status == passed || r.status == failed) {
+			output := r.output
+			return func() tea.Msg {
+				return common.ShowDiffMsg(output)
+			}
+		}
+	case key.Matches(msg, o.keyMap.Run.Rerun):
+		r := o.resultOf(o.current)
+		if r == nil {
+			if o.current == nil {
+				return nil
+			}
+			r = &result{revision: o.current.GetChangeId()}
+			o.results = append(o.results, r)
+		}
+		if r.status == running {
+			return nil
+		}
+		r.status = queued
+		r.output = ""
+		return o.runNext()
+	}
+	return nil
+}
+
+func (o *Operation) HandleMsg(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(resultMsg); ok {
+		r := o.results[msg.index]
+		r.output = msg.output
+		r.duration = msg.duration
+		r.status = passed
+		if msg.err != nil {
+			r.status = failed
+			if r.output == "" {
+				r.output = msg.err.Error()
+			}
+		}
+		return o.runNext()
+	}
+	return nil
+}
+
+// runNext starts the next queued revision unless one is already running.
+// Revisions are run one at a time since each of them creates a workspace.
+func (o *Operation) runNext() tea.Cmd {
+	if slices.ContainsFunc(o.results, func(r *result) bool { return r.status == running }) {
+		return nil
+	}
+	index := slices.IndexFunc(o.results, func(r *result) bool { return r.status == queued })
+	if index == -1 {
+		return nil
+	}
+	r := o.results[index]
+	r.status = running
+	revision := r.revision
+	ctx := o.ctx
+	return func() tea.Msg {
+		start := time.Now()
+		output, err := o.execute(ctx, revision)
+		return resultMsg{index: index, output: output, err: err, duration: time.Since(start)}
+	}
+}
+
+// execute runs the command in a temporary workspace checked out at the revision, so that each
+// revision is tested against its own tree
+func (o *Operation) execute(ctx context.Context, revision string) (string, error) {
+	command := strings.ReplaceAll(o.command, jj.ChangeIdPlaceholder, revision)
+	dir, err := os.MkdirTemp("", "jjui-run-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	name := filepath.Base(dir)
+	if _, err := o.context.RunCommandImmediate(jj.WorkspaceAdd(name, revision, dir)); err != nil {
+		return "", err
+	}
+	defer o.context.RunCommandImmediate(jj.WorkspaceForget(name))
+
+	c := exec.CommandContext(ctx, common.Shell(), "-c", command)
+	c.Dir = dir
+	return o.combinedOutput(c)
+}
+
+// combinedOutput runs the command and records it in the command log
+func (o *Operation) combinedOutput(c *exec.Cmd) (string, error) {
+	var output bytes.Buffer
+	c.Stdout = &output
+	c.Stderr = &output
+	_, _, err := o.context.CommandLog.Run(c, false)
+	return output.String(), err
+}
+
+func (o *Operation) resultOf(commit *jj.Commit) *result {
+	if commit == nil {
+		return nil
+	}
+	for _, r := range o.results {
+		// revisions resolved from a revset are the shortest unique prefixes of change ids
+		if strings.HasPrefix(commit.GetChangeId(), r.revision) {
+			return r
+		}
+	}
+	return nil
+}
+
+func (o *Operation) Render(commit *jj.Commit, pos operations.RenderPosition) string {
+	r := o.resultOf(commit)
+	if r == nil {
+		return ""
+	}
+	switch pos {
+	case operations.RenderBeforeChangeId:
+		switch r.status {
+		case queued:
+			return o.styles.dimmed.Render("◌")
+		case running:
+			return o.styles.running.Render("●")
+		case passed:
+			return o.styles.success.Render("✓")
+		case failed:
+			return o.styles.error.Render("✗")
+		}
+	case operations.RenderPositionAfter:
+		if o.current == nil || o.current.GetChangeId() != commit.GetChangeId() {
+			return ""
+		}
+		return o.summary(r)
+	}
+	return ""
+}
+
+func (o *Operation) summary(r *result) string {
+	var counts [4]int
+	for _, other := range o.results {
+		counts[other.status]++
+	}
+	var state string
+	switch r.status {
+	case queued:
+		state = o.styles.dimmed.Render("queued")
+	case running:
+		state = o.styles.running.Render("running")
+	case passed:
+		state = o.styles.success.Render(fmt.Sprintf("passed in %s", r.duration.Round(time.Millisecond)))
+	case failed:
+		state = o.styles.error.Render(fmt.Sprintf("failed in %s", r.duration.Round(time.Millisecond)))
+	}
+	return lipgloss.JoinHorizontal(0,
+		o.styles.changeId.Render(r.revision),
+		o.styles.dimmed.Render(" "),
+		state,
+		o.styles.dimmed.Render(fmt.Sprintf(" (%d passed, %d failed, %d running, %d queued)", counts[passed], counts[failed], counts[running], counts[queued])),
+	)
+}
+
+func (o *Operation) Name() string {
+	return "run"
+}
+
+// resolveRevisions returns the checked revisions, or the revisions of the configured revset
+// relative to the selected revision, ordered from oldest to newest.
+func resolveRevisions(ctx *appContext.MainContext, current *jj.Commit, checked []*jj.Commit) ([]string, error) {
+	var revset string
+	switch {
+	case len(checked) > 0:
+		// the checked revisions are in the order they are shown, they are sorted by jj like the revset
+		revset = strings.Join(jj.NewSelectedRevisions(checked...).GetIds(), " | ")
+	case current == nil:
+		return nil, nil
+	default:
+		revset = strings.ReplaceAll(config.Current.Run.Revset, jj.ChangeIdPlaceholder, current.GetChangeId())
+		if revset == "" {
+			return []string{current.GetChangeId()}, nil
+		}
+	}
+	output, err := ctx.RunCommandImmediate(jj.GetIdsFromRevset(revset))
+	if err != nil {
+		return nil, err
+	}
+	var revisions []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			revisions = append(revisions, line)
+		}
+	}
+	slices.Reverse(revisions)
+	return revisions, nil
+}
+
+func NewOperation(c *appContext.MainContext, current *jj.Commit, checked []*jj.Commit) (operations.Operation, tea.Cmd) {
+	fail := func(err error) (operations.Operation, tea.Cmd) {
+		return operations.NewDefault(c), func() tea.Msg {
+			return common.CommandCompletedMsg{Err: err}
+		}
+	}
+	if strings.TrimSpace(config.Current.Run.Command) == "" {
+		return fail(errors.New("no command to run, set `command` in the [run] section of the configuration"))
+	}
+	revisions, err := resolveRevisions(c, current, checked)
+	if err != nil {
+		return fail(err)
+	}
+	if len(revisions) == 0 {
+		return fail(errors.New("no revisions to run the command on"))
+	}
+
+	var results []*result
+	for _, revision := range revisions {
+		results = append(results, &result{revision: revision})
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	op := &Operation{
+		context: c,
+		keyMap:  config.Current.GetKeyMap(),
+		command: config.Current.Run.Command,
+		results: results,
+		current: current,
+		ctx:     ctx,
+		cancel:  cancel,
+		styles: styles{
+			dimmed:   common.DefaultPalette.Get("run dimmed"),
+			running:  common.DefaultPalette.Get("run shortcut"),
+			success:  common.DefaultPalette.Get("run success"),
+			error:    common.DefaultPalette.Get("run error"),
+			changeId: common.DefaultPalette.Get("run change_id"),
+		},
+	}
+	return op, op.runNext()
+}
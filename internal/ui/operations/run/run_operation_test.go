@@ -0,0 +1,82 @@
+package run
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/operations"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var commit = &jj.Commit{ChangeId: "kmtnvwpx", CommitId: "0a1b2c3d"}
+
+func Test_resolveRevisions_UsesRevsetOldestFirst(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetIdsFromRevset("trunk()..kmtnvwpx")).SetOutput([]byte("km\nzq\nxy\n"))
+	defer commandRunner.Verify()
+
+	revset := config.Current.Run.Revset
+	config.Current.Run.Revset = "trunk()..$change_id"
+	defer func() { config.Current.Run.Revset = revset }()
+
+	revisions, err := resolveRevisions(test.NewTestContext(commandRunner), commit, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"xy", "zq", "km"}, revisions)
+}
+
+func Test_resolveRevisions_PrefersCheckedRevisions(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetIdsFromRevset("a | b")).SetOutput([]byte("a\nb\n"))
+	defer commandRunner.Verify()
+
+	// a is shown above b, it is newer and runs after b like in the revset
+	checked := []*jj.Commit{{ChangeId: "a"}, {ChangeId: "b"}}
+	revisions, err := resolveRevisions(test.NewTestContext(commandRunner), commit, checked)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, revisions)
+}
+
+func Test_HandleMsg_RunsRevisionsOneAtATime(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetIdsFromRevset("a | b")).SetOutput([]byte("b\na\n"))
+	defer commandRunner.Verify()
+
+	original := config.Current.Run
+	config.Current.Run = config.RunConfig{Command: "make test"}
+	defer func() { config.Current.Run = original }()
+
+	checked := []*jj.Commit{{ChangeId: "a"}, {ChangeId: "b"}}
+	op, cmd := NewOperation(test.NewTestContext(commandRunner), commit, checked)
+	assert.NotNil(t, cmd)
+	o := op.(*Operation)
+	assert.Equal(t, running, o.results[0].status)
+	assert.Equal(t, queued, o.results[1].status)
+
+	cmd = o.HandleMsg(resultMsg{index: 0, output: "ok"})
+	assert.NotNil(t, cmd)
+	assert.Equal(t, passed, o.results[0].status)
+	assert.Equal(t, running, o.results[1].status)
+
+	cmd = o.HandleMsg(resultMsg{index: 1, err: errors.New("exit status 1")})
+	assert.Nil(t, cmd)
+	assert.Equal(t, failed, o.results[1].status)
+	assert.Equal(t, "exit status 1", o.results[1].output)
+	assert.Contains(t, o.Render(checked[0], operations.RenderBeforeChangeId), "✓")
+	assert.Contains(t, o.Render(checked[1], operations.RenderBeforeChangeId), "✗")
+	assert.Empty(t, o.Render(commit, operations.RenderBeforeChangeId))
+}
+
+func Test_NewOperation_WithoutCommand(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	original := config.Current.Run
+	config.Current.Run = config.RunConfig{}
+	defer func() { config.Current.Run = original }()
+
+	op, _ := NewOperation(test.NewTestContext(commandRunner), commit, nil)
+	assert.Equal(t, "normal", op.Name())
+}
@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/keysequence"
+)
+
+type PickMode string
+
+const (
+	PickRevisions PickMode = "revisions"
+	PickFiles     PickMode = "files"
+)
+
+// DefaultPickFormat returns the format of the printed lines when no format is given
+func DefaultPickFormat(mode PickMode) string {
+	if mode == PickFiles {
+		return jj.FilePlaceholder
+	}
+	return jj.ChangeIdPlaceholder
+}
+
+// Picker restricts the UI to choosing revisions or the files of a revision; apply picks the
+// checked items, or the selected one when nothing is checked, and quits
+type Picker struct {
+	model  Model
+	mode   PickMode
+	format string
+	picked []string
+}
+
+// Picked returns the picked items formatted as lines, it is empty when picking is cancelled
+func (p *Picker) Picked() []string {
+	return p.picked
+}
+
+func (p *Picker) Init() tea.Cmd {
+	return p.model.Init()
+}
+
+func (p *Picker) View() string {
+	return p.model.View()
+}
+
+// allowed are the keys which don't change the repository
+func (p *Picker) allowed(msg tea.KeyMsg) bool {
+	km := p.model.keyMap
+	bindings := []key.Binding{
+		km.Up, km.Down, km.JumpToParent, km.JumpToChildren, km.JumpToWorkingCopy, km.ToggleSelect,
+		km.JumpToTop, km.JumpToBottom, km.HalfPageDown, km.HalfPageUp,
+		km.Cancel, km.Refresh, km.Revset, km.QuickSearch, km.QuickSearchCycle, km.AceJump, km.Help,
+		km.Preview.Mode, km.Preview.ToggleBottom, km.Preview.Expand, km.Preview.Shrink,
+		km.Preview.ScrollUp, km.Preview.ScrollDown, km.Preview.HalfPageUp, km.Preview.HalfPageDown,
+	}
+	if p.mode == PickFiles {
+		bindings = append(bindings, km.Details.Mode, km.Details.Close, km.Details.Diff)
+	}
+	return slices.ContainsFunc(bindings, func(b key.Binding) bool { return key.Matches(msg, b) })
+}
+
+// capturesKeys reports whether the keys go to a text input, the diff viewer or ace jump as usual
+func (p *Picker) capturesKeys() bool {
+	m := p.model
+	return m.diff != nil || m.revsetModel.Editing || m.status.IsFocused() || m.revisions.IsFocused() || m.revisions.IsAceJumping()
+}
+
+func (p *Picker) inDetails() bool {
+	return p.model.revisions.CurrentOperation().Name() == "details"
+}
+
+func (p *Picker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case keysequence.KeyMsg:
+		return p.handleKey(tea.KeyMsg(msg))
+	case tea.KeyMsg:
+		// key sequences and counts resolve to the keys which are checked below
+		if !p.capturesKeys() && p.model.acceptsKeySequences(msg) {
+			if cmd, handled := p.model.keySequence.Update(msg); handled {
+				return p, cmd
+			}
+		}
+		return p.handleKey(msg)
+	}
+	model, cmd := p.model.Update(msg)
+	p.model = model.(Model)
+	return p, cmd
+}
+
+func (p *Picker) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !p.capturesKeys() {
+		km := p.model.keyMap
+		switch {
+		case key.Matches(msg, km.Apply):
+			return p.apply()
+		case key.Matches(msg, km.Quit):
+			return p, tea.Quit
+		case key.Matches(msg, km.Cancel) && p.model.stacked == nil && p.model.revisions.InNormalMode():
+			return p, tea.Quit
+		case !p.allowed(msg) && p.model.stacked == nil:
+			return p, nil
+		}
+	}
+	model, cmd := p.model.update(msg)
+	p.model = model.(Model)
+	return p, cmd
+}
+
+func (p *Picker) apply() (tea.Model, tea.Cmd) {
+	if p.model.stacked != nil {
+		return p, nil
+	}
+	if p.mode == PickFiles && !p.inDetails() {
+		if selected, ok := p.model.context.SelectedItem.(context.SelectedRevision); ok {
+			return p, func() tea.Msg {
+				return common.ShowDetailsMsg{Revision: selected.CommitId}
+			}
+		}
+		return p, nil
+	}
+	p.picked = p.render()
+	if len(p.picked) == 0 {
+		return p, nil
+	}
+	return p, tea.Quit
+}
+
+// render formats the checked items of the picked kind, or the selected item when none is checked
+func (p *Picker) render() []string {
+	c := p.model.context
+	items := slices.Clone(c.CheckedItems)
+	if len(items) == 0 || !slices.ContainsFunc(items, p.accepts) {
+		items = []context.SelectedItem{c.SelectedItem}
+	}
+	var lines []string
+	for _, item := range items {
+		if !p.accepts(item) {
+			continue
+		}
+		replacements := map[string]string{}
+		switch item := item.(type) {
+		case context.SelectedRevision:
+			replacements[jj.ChangeIdPlaceholder] = item.ChangeId
+			replacements[jj.CommitIdPlaceholder] = item.CommitId
+		case context.SelectedFile:
+			replacements[jj.ChangeIdPlaceholder] = item.ChangeId
+			replacements[jj.CommitIdPlaceholder] = item.CommitId
+			replacements[jj.FilePlaceholder] = item.File
+		}
+		line := p.format
+		for placeholder, value := range replacements {
+			line = strings.ReplaceAll(line, placeholder, value)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (p *Picker) accepts(item context.SelectedItem) bool {
+	switch item.(type) {
+	case context.SelectedRevision:
+		return p.mode == PickRevisions
+	case context.SelectedFile:
+		return p.mode == PickFiles
+	}
+	return false
+}
+
+func NewPicker(c *context.MainContext, mode PickMode, format string) *Picker {
+	if format == "" {
+		format = DefaultPickFormat(mode)
+	}
+	return &Picker{model: New(c).(Model), mode: mode, format: format}
+}
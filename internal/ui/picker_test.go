@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPicker_PicksSelectedRevision(t *testing.T) {
+	ctx := newTestContext(test.NewTestCommandRunner(t))
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "kkmpptxz", CommitId: "abc123"}
+	picker := NewPicker(ctx, PickRevisions, "")
+
+	_, cmd := picker.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, tea.Quit(), cmd())
+	assert.Equal(t, []string{"kkmpptxz"}, picker.Picked())
+}
+
+func TestPicker_PicksCheckedRevisionsWithFormat(t *testing.T) {
+	ctx := newTestContext(test.NewTestCommandRunner(t))
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "kkmpptxz", CommitId: "abc123"}
+	ctx.CheckedItems = []context.SelectedItem{
+		context.SelectedRevision{ChangeId: "qpvuntsm", CommitId: "def456"},
+		context.SelectedRevision{ChangeId: "zzzzzzzz", CommitId: "000000"},
+	}
+	picker := NewPicker(ctx, PickRevisions, "$change_id:$commit_id")
+
+	picker.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, []string{"qpvuntsm:def456", "zzzzzzzz:000000"}, picker.Picked())
+}
+
+func TestPicker_PicksCheckedFiles(t *testing.T) {
+	ctx := newTestContext(test.NewTestCommandRunner(t))
+	picker := NewPicker(ctx, PickFiles, "")
+	ctx.SelectedItem = context.SelectedFile{ChangeId: "kkmpptxz", CommitId: "abc123", File: "main.go"}
+	ctx.CheckedItems = []context.SelectedItem{
+		context.SelectedRevision{ChangeId: "kkmpptxz", CommitId: "abc123"},
+		context.SelectedFile{ChangeId: "kkmpptxz", CommitId: "abc123", File: "go.mod"},
+	}
+
+	assert.Equal(t, []string{"go.mod"}, picker.render())
+}
+
+func TestPicker_IgnoresKeysChangingTheRepository(t *testing.T) {
+	// the test command runner fails the test when a command is run
+	ctx := newTestContext(test.NewTestCommandRunner(t))
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "kkmpptxz", CommitId: "abc123"}
+	picker := NewPicker(ctx, PickRevisions, "")
+
+	for _, k := range []string{"n", "a", "e", "D", "u"} {
+		_, cmd := picker.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		assert.Nil(t, cmd, k)
+	}
+	assert.Empty(t, picker.Picked())
+}
+
+func TestPicker_CancelPicksNothing(t *testing.T) {
+	ctx := newTestContext(test.NewTestCommandRunner(t))
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "kkmpptxz", CommitId: "abc123"}
+	picker := NewPicker(ctx, PickRevisions, "")
+
+	_, cmd := picker.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, tea.Quit(), cmd())
+	assert.Empty(t, picker.Picked())
+}
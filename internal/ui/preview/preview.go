@@ -87,7 +87,7 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 	case common.SelectionChangedMsg, common.RefreshMsg:
 		m.tag++
 		tag := m.tag
-		return m, tea.Tick(DebounceTime, func(t time.Time) tea.Msg {
+		return m, common.Tick(DebounceTime, func(t time.Time) tea.Msg {
 			return refreshPreviewContentMsg{Tag: tag}
 		})
 	case refreshPreviewContentMsg:
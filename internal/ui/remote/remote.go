@@ -0,0 +1,195 @@
+package remote
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+)
+
+// SocketEnv is the environment variable holding the path of the socket, it is passed to
+// the commands run from jjui
+const SocketEnv = "JJUI_SOCKET"
+
+// Request is a command sent to the socket as a single line of JSON, e.g.
+// `{"command": "select", "revision": "kkmpptxz"}`
+type Request struct {
+	// Command is one of select, revset, refresh, details and flash
+	Command  string `json:"command"`
+	Revision string `json:"revision,omitempty"`
+	Revset   string `json:"revset,omitempty"`
+	File     string `json:"file,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Error    bool   `json:"error,omitempty"`
+}
+
+// Response is written back as a single line of JSON for each request
+type Response struct {
+	Ok    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// SocketPath returns the path of the socket of the repository at the location; it is kept
+// in the runtime directory since the length of socket paths is limited
+func SocketPath(location string) string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	hash := sha256.Sum256([]byte(location))
+	return filepath.Join(dir, "jjui", fmt.Sprintf("%x.sock", hash[:8]))
+}
+
+// Server accepts the requests sent to the socket and passes them to the program as messages
+type Server struct {
+	context  *context.MainContext
+	listener net.Listener
+	path     string
+	send     func(tea.Msg)
+}
+
+// Listen creates the socket at the path, it fails when another jjui is listening on it
+func Listen(c *context.MainContext, path string, send func(tea.Msg)) (*Server, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("another jjui is listening on %s", path)
+		}
+		// the socket is left behind by a jjui which didn't exit cleanly
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return &Server{context: c, listener: listener, path: path, send: send}, nil
+}
+
+// Serve accepts connections until the server is closed
+func (s *Server) Serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Println("remote: failed to accept connection", err)
+			}
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		response := Response{Ok: true}
+		var request Request
+		if err := json.Unmarshal([]byte(line), &request); err != nil {
+			response = Response{Error: "invalid request: " + err.Error()}
+		} else if msg, err := s.message(request); err != nil {
+			response = Response{Error: err.Error()}
+		} else {
+			s.send(msg)
+		}
+		if err := encoder.Encode(response); err != nil {
+			return
+		}
+	}
+}
+
+// message turns the request into the message handled by the UI
+func (s *Server) message(request Request) (tea.Msg, error) {
+	switch request.Command {
+	case "select":
+		commitId, err := s.resolve(request.Revision)
+		if err != nil {
+			return nil, err
+		}
+		// the revisions select @ instead of a revision which is not shown
+		if err := s.shown(request.Revision, commitId); err != nil {
+			return nil, err
+		}
+		return common.RefreshMsg{SelectedRevision: commitId, KeepSelections: true}, nil
+	case "revset":
+		if strings.TrimSpace(request.Revset) == "" {
+			return nil, errors.New("revset is required")
+		}
+		return common.UpdateRevSetMsg(request.Revset), nil
+	case "refresh":
+		return common.RefreshMsg{KeepSelections: true}, nil
+	case "details":
+		commitId, err := s.resolve(request.Revision)
+		if err != nil {
+			return nil, err
+		}
+		return common.ShowDetailsMsg{Revision: commitId, File: request.File}, nil
+	case "flash":
+		if request.Text == "" {
+			return nil, errors.New("text is required")
+		}
+		return common.FlashMsg{Text: request.Text, Error: request.Error}, nil
+	}
+	return nil, fmt.Errorf("unknown command %q", request.Command)
+}
+
+// resolve returns the commit id of the revision, which can be any expression resolving to a single revision
+func (s *Server) resolve(revision string) (string, error) {
+	if strings.TrimSpace(revision) == "" {
+		return "", errors.New("revision is required")
+	}
+	output, err := s.context.RunCommandImmediate(jj.GetCommitIdsFromRevset(revision))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %s", revision, strings.TrimSpace(err.Error()))
+	}
+	ids := strings.Fields(string(output))
+	if len(ids) != 1 {
+		return "", fmt.Errorf("%s resolves to %d revisions", revision, len(ids))
+	}
+	return ids[0], nil
+}
+
+// shown returns an error when the commit is not in the current revset
+func (s *Server) shown(revision string, commitId string) error {
+	revset := fmt.Sprintf("%s & (%s)", commitId, s.context.CurrentRevset)
+	output, err := s.context.RunCommandImmediate(jj.GetCommitIdsFromRevset(revset))
+	if err != nil {
+		return fmt.Errorf("failed to check %s: %s", revision, strings.TrimSpace(err.Error()))
+	}
+	if strings.TrimSpace(string(output)) == "" {
+		return fmt.Errorf("%s is not in the current revset", revision)
+	}
+	return nil
+}
+
+// Close stops listening and removes the socket
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	os.Remove(s.path)
+	return err
+}
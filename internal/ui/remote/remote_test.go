@@ -0,0 +1,125 @@
+package remote
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type client struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+}
+
+func (c *client) send(t *testing.T, request string) Response {
+	_, err := c.conn.Write([]byte(request + "\n"))
+	require.NoError(t, err)
+	require.True(t, c.scanner.Scan())
+	var response Response
+	require.NoError(t, json.Unmarshal(c.scanner.Bytes(), &response))
+	return response
+}
+
+func serve(t *testing.T, commandRunner *test.CommandRunner) (*client, chan tea.Msg) {
+	messages := make(chan tea.Msg, 10)
+	path := filepath.Join(t.TempDir(), "jjui.sock")
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CurrentRevset = "::@"
+	server, err := Listen(ctx, path, func(msg tea.Msg) {
+		messages <- msg
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { server.Close() })
+	go server.Serve()
+
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return &client{conn: conn, scanner: bufio.NewScanner(conn)}, messages
+}
+
+func TestServer_Select(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetCommitIdsFromRevset("kkmpptxz")).SetOutput([]byte("abcdef123456\n"))
+	commandRunner.Expect(jj.GetCommitIdsFromRevset("abcdef123456 & (::@)")).SetOutput([]byte("abcdef123456\n"))
+	defer commandRunner.Verify()
+
+	c, messages := serve(t, commandRunner)
+	assert.Equal(t, Response{Ok: true}, c.send(t, `{"command": "select", "revision": "kkmpptxz"}`))
+	assert.Equal(t, common.RefreshMsg{SelectedRevision: "abcdef123456", KeepSelections: true}, <-messages)
+}
+
+func TestServer_SelectOutsideOfRevset(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetCommitIdsFromRevset("trunk()")).SetOutput([]byte("abcdef123456\n"))
+	commandRunner.Expect(jj.GetCommitIdsFromRevset("abcdef123456 & (::@)"))
+	defer commandRunner.Verify()
+
+	c, messages := serve(t, commandRunner)
+	assert.Equal(t, "trunk() is not in the current revset", c.send(t, `{"command": "select", "revision": "trunk()"}`).Error)
+	assert.Empty(t, messages)
+}
+
+func TestServer_Details(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetCommitIdsFromRevset("@-")).SetOutput([]byte("abcdef123456\n"))
+	defer commandRunner.Verify()
+
+	c, messages := serve(t, commandRunner)
+	assert.Equal(t, Response{Ok: true}, c.send(t, `{"command": "details", "revision": "@-", "file": "main.go"}`))
+	assert.Equal(t, common.ShowDetailsMsg{Revision: "abcdef123456", File: "main.go"}, <-messages)
+}
+
+func TestServer_Commands(t *testing.T) {
+	c, messages := serve(t, test.NewTestCommandRunner(t))
+
+	assert.Equal(t, Response{Ok: true}, c.send(t, `{"command": "revset", "revset": "mine()"}`))
+	assert.Equal(t, common.UpdateRevSetMsg("mine()"), <-messages)
+
+	assert.Equal(t, Response{Ok: true}, c.send(t, `{"command": "refresh"}`))
+	assert.Equal(t, common.RefreshMsg{KeepSelections: true}, <-messages)
+
+	assert.Equal(t, Response{Ok: true}, c.send(t, `{"command": "flash", "text": "saved", "error": true}`))
+	assert.Equal(t, common.FlashMsg{Text: "saved", Error: true}, <-messages)
+}
+
+func TestServer_InvalidRequests(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetCommitIdsFromRevset("trunk()..@")).SetOutput([]byte("abc\ndef\n"))
+	defer commandRunner.Verify()
+
+	c, messages := serve(t, commandRunner)
+	assert.Equal(t, `unknown command "jump"`, c.send(t, `{"command": "jump"}`).Error)
+	assert.Equal(t, "revision is required", c.send(t, `{"command": "select"}`).Error)
+	assert.Equal(t, "trunk()..@ resolves to 2 revisions", c.send(t, `{"command": "select", "revision": "trunk()..@"}`).Error)
+	assert.Contains(t, c.send(t, `not json`).Error, "invalid request")
+	assert.Empty(t, messages)
+}
+
+func TestListen_FailsWhenAnotherServerIsListening(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jjui.sock")
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	server, err := Listen(ctx, path, func(tea.Msg) {})
+	require.NoError(t, err)
+	defer server.Close()
+
+	_, err = Listen(ctx, path, func(tea.Msg) {})
+	assert.Error(t, err)
+}
+
+func TestSocketPath(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	path := SocketPath("/home/user/repo")
+	assert.Equal(t, "/run/user/1000/jjui", filepath.Dir(path))
+	assert.Equal(t, path, SocketPath("/home/user/repo"))
+	assert.NotEqual(t, path, SocketPath("/home/user/other"))
+}
@@ -0,0 +1,51 @@
+package revisions
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/parser"
+	"github.com/idursun/jjui/internal/ui/common"
+	appContext "github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/graph"
+	"github.com/idursun/jjui/internal/ui/operations"
+)
+
+// Print renders the revisions of the revset the way they are shown when jjui starts, i.e. with
+// the working copy highlighted, and writes them to w. The escape sequences are removed when
+// plain is set.
+func Print(c *appContext.MainContext, w io.Writer, revset string, width int, plain bool) error {
+	output, err := c.RunCommandImmediate(jj.Log(revset, config.Current.Limit))
+	if err != nil {
+		// the error is the stderr of jj, the caller prefixes it with "Error: " already
+		return errors.New(strings.TrimPrefix(strings.TrimSpace(err.Error()), "Error: "))
+	}
+	rows := parser.ParseRows(bytes.NewReader(output))
+	iterator := graph.NewDefaultRowIterator(rows, graph.WithWidth(width), graph.WithStylePrefix("revisions"))
+	iterator.Op = operations.NewDefault(c)
+	iterator.Cursor = slices.IndexFunc(rows, func(row parser.Row) bool {
+		return row.Commit != nil && row.Commit.IsWorkingCopy
+	})
+
+	var rendered strings.Builder
+	for iterator.Next() {
+		iterator.Render(&rendered)
+	}
+	view := strings.TrimSuffix(rendered.String(), "\n")
+	view = common.DefaultPalette.Get("revisions text").MaxWidth(width).Render(view)
+	for _, line := range strings.Split(view, "\n") {
+		if plain {
+			line = strings.TrimRight(ansi.Strip(line), " ")
+		}
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package revisions
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrint_Plain(t *testing.T) {
+	var lb test.LogBuilder
+	lb.Write("@  id=kkmpptxz author=some@author id=8b1e95e3")
+	lb.Write("│  working copy")
+	lb.Write("○  id=nyqzpsmt author=some@author id=5233c94f")
+	lb.Write("│  a long description which doesn't fit")
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Log("all()", config.Current.Limit)).SetOutput([]byte(lb.String()))
+	defer commandRunner.Verify()
+
+	var out bytes.Buffer
+	err := Print(test.NewTestContext(commandRunner), &out, "all()", 30, true)
+	require.NoError(t, err)
+	assert.Equal(t, `@  kkmpptxz  some@author  8b1e
+│ working copy
+○  nyqzpsmt  some@author  5233
+│ a long description which doe
+`, out.String())
+}
+
+func TestPrint_Failure(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	// the runner returns no output and the stderr of jj as the error when jj fails
+	commandRunner.Expect(jj.Log("bad(", config.Current.Limit)).SetError(errors.New("Error: Failed to parse revset\n"))
+	defer commandRunner.Verify()
+
+	var out bytes.Buffer
+	err := Print(test.NewTestContext(commandRunner), &out, "bad(", 30, true)
+	assert.EqualError(t, err, "Failed to parse revset")
+	assert.Empty(t, out.String())
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/idursun/jjui/internal/ui/operations/details"
 	"github.com/idursun/jjui/internal/ui/operations/evolog"
 	"github.com/idursun/jjui/internal/ui/operations/rebase"
+	"github.com/idursun/jjui/internal/ui/operations/run"
 	"github.com/idursun/jjui/internal/ui/operations/squash"
 )
 
@@ -54,6 +55,7 @@ type Model struct {
 	quickSearch       string
 	selectedRevisions map[string]bool
 	previousOpLogId   string
+	failedStatus      string
 	isLoading         bool
 	w                 *graph.Renderer
 	textStyle         lipgloss.Style
@@ -75,6 +77,20 @@ type updateRevisionsMsg struct {
 	selectedRevision string
 }
 
+type updateStatusesMsg struct {
+	statuses []context.CommitStatus
+}
+
+// statusesFailedMsg stops running the status command until it is changed in the config
+type statusesFailedMsg struct {
+	command string
+	err     error
+}
+
+type updateReviewsMsg struct {
+	changeIds map[string]string
+}
+
 type startRowsStreamingMsg struct {
 	selectedRevision string
 	tag              uint64
@@ -93,6 +109,11 @@ func (m *Model) IsFocused() bool {
 	return false
 }
 
+// IsLoading reports whether the revisions are being loaded
+func (m *Model) IsLoading() bool {
+	return m.isLoading
+}
+
 func (m *Model) InNormalMode() bool {
 	if _, ok := m.op.(*operations.Default); ok {
 		return true
@@ -137,13 +158,18 @@ func (m *Model) SelectedRevision() *jj.Commit {
 	return m.rows[m.cursor].Commit
 }
 
-func (m *Model) SelectedRevisions() jj.SelectedRevisions {
-	var selected []*jj.Commit
+func (m *Model) checkedRevisions() []*jj.Commit {
+	var checked []*jj.Commit
 	for _, row := range m.rows {
 		if m.selectedRevisions[row.Commit.GetChangeId()] {
-			selected = append(selected, row.Commit)
+			checked = append(checked, row.Commit)
 		}
 	}
+	return checked
+}
+
+func (m *Model) SelectedRevisions() jj.SelectedRevisions {
+	selected := m.checkedRevisions()
 	if len(selected) == 0 {
 		return jj.NewSelectedRevisions(m.SelectedRevision())
 	}
@@ -160,20 +186,41 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 	}
 	switch msg := msg.(type) {
 	case common.CloseViewMsg:
-		m.op = operations.NewDefault()
+		m.op = operations.NewDefault(m.context)
 		return m, m.updateSelection()
 	case common.UpdateRevSetMsg:
 		return m, common.Refresh
 	case common.QuickSearchMsg:
 		m.quickSearch = string(msg)
 		m.cursor = m.search(0)
-		m.op = operations.NewDefault()
+		m.op = operations.NewDefault(m.context)
 		m.w.ResetViewRange()
 		return m, nil
 	case common.CommandCompletedMsg:
 		m.output = msg.Output
 		m.err = msg.Err
 		return m, nil
+	case common.ShowDetailsMsg:
+		// an operation in progress, like a rebase, would be lost
+		if !m.InNormalMode() {
+			return m, func() tea.Msg {
+				return common.FlashMsg{Text: fmt.Sprintf("can't show the details of %s while %s is in progress", msg.Revision, m.op.Name()), Error: true}
+			}
+		}
+		index := m.selectRevision(msg.Revision)
+		if index == -1 {
+			return m, func() tea.Msg {
+				return common.FlashMsg{Text: fmt.Sprintf("%s is not in the current revset", msg.Revision), Error: true}
+			}
+		}
+		m.cursor = index
+		commit := m.rows[index].Commit
+		var cmd tea.Cmd
+		m.op, cmd = details.NewOperationWithFile(m.context, commit, msg.File)
+		if op, ok := m.op.(operations.TracksSelectedRevision); ok {
+			op.SetSelectedRevision(commit)
+		}
+		return m, tea.Batch(cmd, m.updateSelection())
 	case common.EditRevision:
 		cmd := m.context.RunCommand(jj.Edit(string(msg)), common.Refresh)
 		return m, cmd
@@ -185,6 +232,22 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 			m.previousOpLogId = currentOperationId
 			return m, common.RefreshAndKeepSelections
 		}
+		// statuses can change without any change in the repository
+		return m, m.loadStatuses()
+	case updateStatusesMsg:
+		m.context.Statuses.Set(msg.statuses)
+		return m, nil
+	case statusesFailedMsg:
+		if m.failedStatus == msg.command {
+			return m, nil
+		}
+		m.failedStatus = msg.command
+		return m, func() tea.Msg {
+			return common.FlashMsg{Text: msg.err.Error(), Error: true}
+		}
+	case updateReviewsMsg:
+		m.context.Reviews.SetCommits(msg.changeIds)
+		return m, nil
 	case common.RefreshMsg:
 		if !msg.KeepSelections {
 			m.selectedRevisions = make(map[string]bool)
@@ -201,7 +264,7 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 	case updateRevisionsMsg:
 		m.isLoading = false
 		m.updateGraphRows(msg.rows, msg.selectedRevision)
-		return m, tea.Batch(m.highlightChanges, m.updateSelection(), func() tea.Msg {
+		return m, tea.Batch(m.highlightChanges, m.updateSelection(), m.loadStatuses(), m.loadReviews(), func() tea.Msg {
 			return common.UpdateRevisionsSuccessMsg{}
 		})
 	case startRowsStreamingMsg:
@@ -243,7 +306,7 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 
 		cmds := []tea.Cmd{m.highlightChanges, m.updateSelection()}
 		if !m.hasMore {
-			cmds = append(cmds, func() tea.Msg {
+			cmds = append(cmds, m.loadStatuses(), m.loadReviews(), func() tea.Msg {
 				return common.UpdateRevisionsSuccessMsg{}
 			})
 		}
@@ -268,6 +331,26 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 			} else if m.hasMore {
 				return m, m.requestMoreRows(m.tag)
 			}
+		case key.Matches(msg, m.keymap.JumpToTop):
+			m.cursor = 0
+		case key.Matches(msg, m.keymap.JumpToBottom):
+			if len(m.rows) > 0 {
+				m.cursor = len(m.rows) - 1
+			}
+			if m.hasMore {
+				return m, tea.Batch(m.updateSelection(), m.requestMoreRows(m.tag))
+			}
+		case key.Matches(msg, m.keymap.HalfPageUp):
+			m.cursor = max(m.cursor-m.halfPage(), 0)
+		case key.Matches(msg, m.keymap.HalfPageDown):
+			if m.cursor+m.halfPage() < len(m.rows) {
+				m.cursor += m.halfPage()
+				break
+			}
+			m.cursor = max(len(m.rows)-1, 0)
+			if m.hasMore {
+				return m, tea.Batch(m.updateSelection(), m.requestMoreRows(m.tag))
+			}
 		case key.Matches(msg, m.keymap.JumpToParent):
 			immediate, _ := m.context.RunCommandImmediate(jj.GetParent(m.SelectedRevisions()))
 			parentIndex := m.selectRevision(string(immediate))
@@ -313,7 +396,7 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 					m.cursor = parentIndex
 				}
 			case key.Matches(msg, m.keymap.Cancel):
-				m.op = operations.NewDefault()
+				m.op = operations.NewDefault(m.context)
 			case key.Matches(msg, m.keymap.QuickSearchCycle):
 				m.cursor = m.search(m.cursor + 1)
 				m.w.ResetViewRange()
@@ -370,6 +453,8 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 				m.op = rebase.NewOperation(m.context, m.SelectedRevisions(), rebase.SourceRevision, rebase.TargetDestination)
 			case key.Matches(msg, m.keymap.Duplicate.Mode):
 				m.op = duplicate.NewOperation(m.context, m.SelectedRevisions(), duplicate.TargetDestination)
+			case key.Matches(msg, m.keymap.Run.Mode):
+				m.op, cmd = run.NewOperation(m.context, m.SelectedRevision(), m.checkedRevisions())
 			}
 		}
 	}
@@ -383,6 +468,15 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 	return m, cmd
 }
 
+// halfPage returns half of the number of the rows on the screen, it is at least one row
+func (m *Model) halfPage() int {
+	first, last := m.w.FirstRowIndex(), m.w.LastRowIndex()
+	if first < 0 || last < first {
+		return 1
+	}
+	return max((last-first+1)/2, 1)
+}
+
 func (m *Model) updateSelection() tea.Cmd {
 	if selectedRevision := m.SelectedRevision(); selectedRevision != nil {
 		return m.context.SetSelectedItem(appContext.SelectedRevision{
@@ -479,6 +573,86 @@ func (m *Model) load(revset string, selectedRevision string) tea.Cmd {
 	}
 }
 
+// loadStatuses runs the configured status command for the pushed commits in the view
+func (m *Model) loadStatuses() tea.Cmd {
+	command := config.Current.Status.Command
+	if strings.TrimSpace(command) == "" || command == m.failedStatus {
+		return nil
+	}
+	var ids []string
+	for _, row := range m.rows {
+		if row.Commit != nil && row.Commit.CommitId != "" {
+			ids = append(ids, row.Commit.CommitId)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	revset := fmt.Sprintf("(%s) & ::remote_bookmarks()", strings.Join(ids, " | "))
+	return func() tea.Msg {
+		output, err := m.context.RunCommandImmediate(jj.GetCommitIdsFromRevset(revset))
+		if err != nil {
+			return statusesFailedMsg{command: command, err: fmt.Errorf("failed to resolve pushed commits: %w", err)}
+		}
+		var commitIds []string
+		for _, line := range strings.Split(string(output), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				commitIds = append(commitIds, line)
+			}
+		}
+		if len(commitIds) == 0 {
+			return updateStatusesMsg{}
+		}
+		statuses, err := context.FetchStatuses(m.context.CommandLog, m.context.Location, command, commitIds)
+		if err != nil {
+			return statusesFailedMsg{command: command, err: err}
+		}
+		return updateStatusesMsg{statuses: statuses}
+	}
+}
+
+// loadReviews resolves the full change ids of the revisions in the view which have a review
+func (m *Model) loadReviews() tea.Cmd {
+	reviewed := m.context.Reviews.ChangeIds()
+	if len(reviewed) == 0 {
+		return nil
+	}
+	var ids []string
+	for _, row := range m.rows {
+		if row.Commit != nil && row.Commit.CommitId != "" {
+			ids = append(ids, row.Commit.CommitId)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	for i, changeId := range reviewed {
+		reviewed[i] = fmt.Sprintf("present(%s)", changeId)
+	}
+	revset := fmt.Sprintf("(%s) & (%s)", strings.Join(ids, " | "), strings.Join(reviewed, " | "))
+	return func() tea.Msg {
+		output, err := m.context.RunCommandImmediate(jj.GetChangeIdsOfCommits(revset))
+		if err != nil {
+			log.Println("failed to resolve reviewed revisions:", err)
+			return nil
+		}
+		changeIds := make(map[string]string)
+		for _, line := range strings.Split(string(output), "\n") {
+			commitId, changeId, ok := strings.Cut(strings.TrimSpace(line), ";")
+			if !ok {
+				continue
+			}
+			// the log shows a unique prefix of the commit id
+			for _, id := range ids {
+				if strings.HasPrefix(commitId, id) {
+					changeIds[id] = changeId
+				}
+			}
+		}
+		return updateReviewsMsg{changeIds: changeIds}
+	}
+}
+
 func (m *Model) loadStreaming(revset string, selectedRevision string, tag uint64) tea.Cmd {
 	if m.tag != tag {
 		return nil
@@ -577,7 +751,7 @@ func New(c *appContext.MainContext) Model {
 		keymap:            keymap,
 		rows:              nil,
 		offScreenRows:     nil,
-		op:                operations.NewDefault(),
+		op:                operations.NewDefault(c),
 		cursor:            0,
 		width:             20,
 		height:            10,
@@ -603,5 +777,10 @@ func (m *Model) updateOperation(msg tea.Msg) (tea.Cmd, bool) {
 		m.op, cmd = op.Update(msg)
 		return cmd, true
 	}
+	if op, ok := m.op.(operations.HandleMsg); ok {
+		if _, isKey := msg.(tea.KeyMsg); !isKey {
+			return op.HandleMsg(msg), true
+		}
+	}
 	return nil, false
 }
@@ -1,8 +1,14 @@
 package revisions
 
 import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/parser"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/operations/abandon"
+	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -23,3 +29,76 @@ Parent commit      : nyqzpsmt 8b1e95e3 change third file
 	assert.False(t, model.rows[0].IsAffected)
 	assert.True(t, model.rows[1].IsAffected)
 }
+
+func TestModel_loadReviews(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetChangeIdsOfCommits("(8b1e95e3 | 5233c94f) & (present(nyqzpsmtkvqsrlwoykmzrxnpvlytvuvx))")).
+		SetOutput([]byte("8b1e95e3b2c1d0f9e8a7b6c5d4e3f2a1b0c9d8e7;nyqzpsmtkvqsrlwoykmzrxnpvlytvuvx"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.Reviews = context.NewReviews(t.TempDir())
+	assert.NoError(t, ctx.Reviews.Set("nyqzpsmtkvqsrlwoykmzrxnpvlytvuvx", "42"))
+	model := New(ctx)
+	model.rows = []parser.Row{
+		{Commit: &jj.Commit{ChangeId: "nyqzpsmt", CommitId: "8b1e95e3"}},
+		{Commit: &jj.Commit{ChangeId: "okrwsxvv", CommitId: "5233c94f"}},
+	}
+	model.Update(model.loadReviews()())
+	assert.Equal(t, "42", ctx.Reviews.ForCommit("8b1e95e3"))
+	assert.Empty(t, ctx.Reviews.ForCommit("5233c94f"))
+}
+
+func TestModel_loadStatuses_StopsAfterFailure(t *testing.T) {
+	original := config.Current.Status
+	config.Current.Status.Command = "echo broken >&2; exit 3"
+	defer func() { config.Current.Status = original }()
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetCommitIdsFromRevset("(8b1e95e3) & ::remote_bookmarks()")).SetOutput([]byte("8b1e95e3b2c1d0f9e8a7b6c5d4e3f2a1b0c9d8e7"))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.rows = []parser.Row{{Commit: &jj.Commit{ChangeId: "nyqzpsmt", CommitId: "8b1e95e3"}}}
+	_, cmd := model.Update(model.loadStatuses()())
+	assert.NotNil(t, cmd)
+	flash, ok := cmd().(common.FlashMsg)
+	assert.True(t, ok)
+	assert.True(t, flash.Error)
+	assert.Contains(t, flash.Text, "broken")
+	assert.Nil(t, model.loadStatuses())
+}
+
+func TestModel_ShowDetailsIsRejectedDuringAnOperation(t *testing.T) {
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	model := New(ctx)
+	commit := &jj.Commit{ChangeId: "nyqzpsmt", CommitId: "8b1e95e3"}
+	model.rows = []parser.Row{{Commit: commit}}
+	op := abandon.NewOperation(ctx, jj.NewSelectedRevisions(commit))
+	model.op = op
+
+	_, cmd := model.Update(common.ShowDetailsMsg{Revision: "8b1e95e3"})
+	flash, ok := cmd().(common.FlashMsg)
+	assert.True(t, ok)
+	assert.True(t, flash.Error)
+	assert.Same(t, op, model.op)
+}
+
+func TestModel_JumpToTopBottomAndHalfPage(t *testing.T) {
+	model := New(test.NewTestContext(test.NewTestCommandRunner(t)))
+	for _, changeId := range []string{"a", "b", "c", "d", "e"} {
+		model.rows = append(model.rows, parser.Row{Commit: &jj.Commit{ChangeId: changeId}})
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	assert.Equal(t, 4, model.cursor)
+	model.Update(tea.KeyMsg{Type: tea.KeyHome})
+	assert.Equal(t, 0, model.cursor)
+
+	// the rows on the screen are not known before rendering, a half page is a row then
+	model.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	assert.Equal(t, 1, model.cursor)
+	model.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	model.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	assert.Equal(t, 0, model.cursor)
+}
@@ -1,6 +1,8 @@
 package status
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/ui/command_palette"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
 	"github.com/idursun/jjui/internal/ui/exec_process"
@@ -29,6 +32,7 @@ const (
 	commandRunning
 	commandCompleted
 	commandFailed
+	commandCancelled
 )
 
 type Model struct {
@@ -68,6 +72,11 @@ func (m *Model) IsFocused() bool {
 	return m.editStatus != nil
 }
 
+// InCommandPalette reports whether the command palette is open
+func (m *Model) InCommandPalette() bool {
+	return m.IsFocused() && m.mode == "palette"
+}
+
 func (m *Model) FuzzyView() string {
 	if m.fuzzy == nil {
 		return ""
@@ -110,13 +119,15 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 		m.status = commandRunning
 		return m, m.spinner.Tick
 	case common.CommandCompletedMsg:
-		if msg.Err != nil {
+		if errors.Is(msg.Err, common.ErrCommandCancelled) {
+			m.status = commandCancelled
+		} else if msg.Err != nil {
 			m.status = commandFailed
 		} else {
 			m.status = commandCompleted
 		}
 		commandToBeCleared := m.command
-		return m, tea.Tick(CommandClearDuration, func(time.Time) tea.Msg {
+		return m, common.Tick(CommandClearDuration, func(time.Time) tea.Msg {
 			return clearMsg(commandToBeCleared)
 		})
 	case common.FileSearchMsg:
@@ -125,6 +136,12 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 		m.loadEditingSuggestions()
 		m.fuzzy, m.editStatus = fuzzy_files.NewModel(msg)
 		return m, tea.Batch(m.fuzzy.Init(), m.input.Focus())
+	case common.CommandPaletteMsg:
+		m.mode = "palette"
+		m.input.Prompt = "> "
+		m.loadEditingSuggestions()
+		m.fuzzy, m.editStatus = command_palette.NewModel(m.context)
+		return m, tea.Batch(m.fuzzy.Init(), m.input.Focus())
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, km.Cancel) && m.IsFocused():
@@ -151,7 +168,7 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 			m.input.Reset()
 
 			switch {
-			case strings.HasSuffix(editMode, "file"):
+			case strings.HasSuffix(editMode, "file"), editMode == "palette":
 				_, cmd := fuzzy.Update(msg)
 				return m, cmd
 			case strings.HasPrefix(editMode, "exec"):
@@ -189,7 +206,8 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 		return m, nil
 	default:
 		var cmd tea.Cmd
-		if m.status == commandRunning {
+		// the spinner keeps the jobs indicator up to date while there are commands in the background
+		if m.status == commandRunning || m.jobs() != "" {
 			m.spinner, cmd = m.spinner.Update(msg)
 		}
 		if m.fuzzy != nil {
@@ -221,14 +239,22 @@ func (m *Model) View() string {
 		commandStatusMark = m.styles.text.Render(m.spinner.View())
 	} else if m.status == commandFailed {
 		commandStatusMark = m.styles.error.Render("✗ ")
+	} else if m.status == commandCancelled {
+		commandStatusMark = m.styles.error.Render("⊘ ")
 	} else if m.status == commandCompleted {
 		commandStatusMark = m.styles.success.Render("✓ ")
 	} else {
-		commandStatusMark = m.helpView(m.keyMap)
+		commandStatusMark = m.styles.dimmed.Render(m.jobs()) + m.helpView(m.keyMap)
 		commandStatusMark = lipgloss.PlaceHorizontal(m.width, 0, commandStatusMark, lipgloss.WithWhitespaceBackground(m.styles.text.GetBackground()))
 	}
 	modeWith := 10
 	ret := m.styles.text.Render(strings.ReplaceAll(m.command, "\n", "⏎"))
+	if m.status == commandCancelled {
+		ret += m.styles.dimmed.Render(" (cancelled)")
+	}
+	if m.status != none {
+		ret = m.styles.dimmed.Render(m.jobs()) + ret
+	}
 	if m.IsFocused() {
 		commandStatusMark = ""
 		editKeys, editHelp := m.editStatus()
@@ -245,6 +271,18 @@ func (m *Model) View() string {
 	return lipgloss.Place(m.width, height, 0, 0, ret, lipgloss.WithWhitespaceBackground(m.styles.text.GetBackground()))
 }
 
+// jobs describes the commands which are running or waiting for their turn
+func (m *Model) jobs() string {
+	running, queued := m.context.Jobs.Counts()
+	switch {
+	case running == 0 && queued == 0:
+		return ""
+	case queued == 0:
+		return fmt.Sprintf("[%d running] ", running)
+	}
+	return fmt.Sprintf("[%d running, %d queued] ", running, queued)
+}
+
 func (m *Model) SetHelp(keyMap help.KeyMap) {
 	m.keyMap = keyMap
 }
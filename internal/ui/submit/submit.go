@@ -0,0 +1,308 @@
+package submit
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+)
+
+const (
+	bookmarkPlaceholder    = "$bookmark"
+	basePlaceholder        = "$base"
+	titlePlaceholder       = "$title"
+	descriptionPlaceholder = "$description"
+	reviewPlaceholder      = "$review"
+)
+
+type status int
+
+const (
+	pending status = iota
+	queued
+	running
+	submitted
+	failed
+	skipped
+)
+
+type stackLoadedMsg struct {
+	commits []jj.StackCommit
+	trunk   string
+}
+
+type resultMsg struct {
+	index  int
+	review string
+	err    error
+}
+
+// entry is a revision of the stack together with the review it is submitted to
+type entry struct {
+	commit   jj.StackCommit
+	bookmark string
+	base     string
+	review   string
+	status   status
+	message  string
+}
+
+// Model submits each bookmarked revision of the stack ending at the selected revision for
+// code review by running the configured commands, and records the returned review ids
+type Model struct {
+	context  *context.MainContext
+	keymap   config.KeyMappings[key.Binding]
+	revision string
+	create   string
+	update   string
+	entries  []*entry
+	loaded   bool
+	width    int
+	height   int
+	styles   common.DialogStyles
+}
+
+func (m *Model) Width() int {
+	return m.width
+}
+
+func (m *Model) Height() int {
+	return m.height
+}
+
+func (m *Model) SetWidth(w int) {
+	m.width = w
+}
+
+func (m *Model) SetHeight(h int) {
+	m.height = h
+}
+
+func (m *Model) ShortHelp() []key.Binding {
+	return []key.Binding{m.keymap.Apply, m.keymap.Cancel}
+}
+
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.load
+}
+
+func (m *Model) load() tea.Msg {
+	output, _ := m.context.RunCommandImmediate(jj.Stack(m.revision))
+	msg := stackLoadedMsg{commits: jj.ParseStackOutput(string(output))}
+	if output, err := m.context.RunCommandImmediate(jj.BookmarkList("trunk()")); err == nil {
+		for _, b := range jj.ParseBookmarkListOutput(string(output)) {
+			if b.Local != nil && !b.Conflict {
+				msg.trunk = b.Name
+				break
+			}
+		}
+	}
+	return msg
+}
+
+// newEntries pairs each revision with its first bookmark and bases it on the closest bookmarked
+// revision below it, or on the trunk bookmark for the bottom of the stack
+func (m *Model) newEntries(commits []jj.StackCommit, trunk string) []*entry {
+	var entries []*entry
+	base := trunk
+	for _, commit := range commits {
+		e := &entry{commit: commit, base: base, review: m.context.Reviews.Get(commit.FullChangeId)}
+		if len(commit.Bookmarks) == 0 {
+			e.status = skipped
+			e.message = "no bookmark"
+		} else {
+			e.bookmark = commit.Bookmarks[0]
+			base = e.bookmark
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func (m *Model) submit() tea.Cmd {
+	if strings.TrimSpace(m.create) == "" {
+		return func() tea.Msg {
+			return common.CommandCompletedMsg{Err: errors.New("no command to submit reviews, set `create` in the [submit] section of the configuration")}
+		}
+	}
+	for _, e := range m.entries {
+		if e.status != skipped {
+			e.status = queued
+		}
+	}
+	return m.next()
+}
+
+// next starts submitting the first queued revision, one at a time to keep the order of the stack
+func (m *Model) next() tea.Cmd {
+	if slices.ContainsFunc(m.entries, func(e *entry) bool { return e.status == running }) {
+		return nil
+	}
+	index := slices.IndexFunc(m.entries, func(e *entry) bool { return e.status == queued })
+	if index == -1 {
+		return common.Refresh
+	}
+	e := m.entries[index]
+	e.status = running
+	template := m.create
+	if e.review != "" && strings.TrimSpace(m.update) != "" {
+		template = m.update
+	}
+	return func() tea.Msg {
+		review, err := m.execute(template, *e)
+		if err == nil {
+			err = m.context.Reviews.Set(e.commit.FullChangeId, review)
+		}
+		return resultMsg{index: index, review: review, err: err}
+	}
+}
+
+func (m *Model) execute(template string, e entry) (string, error) {
+	description, err := m.context.RunCommandImmediate(jj.GetDescription(e.commit.ChangeId))
+	if err != nil {
+		return "", err
+	}
+	command := strings.NewReplacer(
+		bookmarkPlaceholder, quote(e.bookmark),
+		basePlaceholder, quote(e.base),
+		titlePlaceholder, quote(e.commit.Description),
+		descriptionPlaceholder, quote(strings.TrimSpace(string(description))),
+		reviewPlaceholder, quote(e.review),
+	).Replace(template)
+
+	c := exec.Command(common.Shell(), "-c", command)
+	c.Dir = m.context.Location
+	output, stderr, err := m.context.CommandLog.Run(c, false)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+	// the review id or url is the last line printed by the command
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	review := strings.TrimSpace(lines[len(lines)-1])
+	if review == "" {
+		return "", errors.New("the submit command did not print a review id")
+	}
+	return review, nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case stackLoadedMsg:
+		m.loaded = true
+		m.entries = m.newEntries(msg.commits, msg.trunk)
+		return m, nil
+	case resultMsg:
+		e := m.entries[msg.index]
+		if msg.err != nil {
+			e.status = failed
+			e.message = strings.TrimSpace(msg.err.Error())
+		} else {
+			e.status = submitted
+			e.review = msg.review
+		}
+		return m, m.next()
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keymap.Cancel):
+			return m, common.Close
+		case key.Matches(msg, m.keymap.Apply):
+			if !m.submitting() && slices.ContainsFunc(m.entries, func(e *entry) bool { return e.status != skipped }) {
+				return m, m.submit()
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) submitting() bool {
+	return slices.ContainsFunc(m.entries, func(e *entry) bool { return e.status == queued || e.status == running })
+}
+
+func (m *Model) View() string {
+	width := max(m.width-m.styles.Border.GetHorizontalFrameSize(), 40)
+	lines := []string{m.styles.Title.Render(fmt.Sprintf("Submit the stack of %s for review", m.revision)), ""}
+
+	switch {
+	case !m.loaded:
+		lines = append(lines, m.styles.Dimmed.Render("loading..."))
+	case len(m.entries) == 0:
+		lines = append(lines, m.styles.Dimmed.Render("There are no revisions between trunk() and "+m.revision))
+	default:
+		idWidth, bookmarkWidth, baseWidth := 0, 0, 0
+		for _, e := range m.entries {
+			idWidth = max(idWidth, lipgloss.Width(e.commit.ChangeId))
+			bookmarkWidth = max(bookmarkWidth, lipgloss.Width(e.bookmark))
+			baseWidth = max(baseWidth, lipgloss.Width(e.base))
+		}
+		// title and help lines with the blank lines around them
+		const chrome = 4
+		listHeight := max(m.height-m.styles.Border.GetVerticalFrameSize()-chrome, 1)
+		start := max(len(m.entries)-listHeight, 0)
+		for _, e := range m.entries[start:] {
+			line := fmt.Sprintf("%s %-*s  %-*s → %-*s  %s", m.marker(e.status), idWidth, e.commit.ChangeId, bookmarkWidth, e.bookmark, baseWidth, e.base, m.state(e))
+			lines = append(lines, m.styles.Text.Width(width).MaxWidth(width).Render(line))
+		}
+	}
+
+	lines = append(lines, "", m.styles.RenderHelp(m.ShortHelp()))
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	content = lipgloss.Place(width, lipgloss.Height(content), 0, 0, content, lipgloss.WithWhitespaceBackground(m.styles.Text.GetBackground()))
+	return m.styles.Border.Render(content)
+}
+
+func (m *Model) marker(s status) string {
+	switch s {
+	case queued:
+		return m.styles.Dimmed.Render("◌")
+	case running:
+		return m.styles.Text.Render("●")
+	case submitted:
+		return m.styles.Success.Render("✓")
+	case failed:
+		return m.styles.Error.Render("✗")
+	case skipped:
+		return m.styles.Dimmed.Render("-")
+	}
+	return " "
+}
+
+func (m *Model) state(e *entry) string {
+	switch {
+	case e.status == failed || e.status == skipped:
+		return m.styles.Dimmed.Render(e.message)
+	case e.review != "":
+		return e.review
+	}
+	return m.styles.Dimmed.Render("new review")
+}
+
+// quote makes the value safe to be used as a single shell word
+func quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func NewModel(c *context.MainContext, revision string, width int, height int) *Model {
+	return &Model{
+		context:  c,
+		keymap:   config.Current.GetKeyMap(),
+		revision: revision,
+		create:   config.Current.Submit.Create,
+		update:   config.Current.Submit.Update,
+		width:    width,
+		height:   height,
+		styles:   common.NewDialogStyles("submit"),
+	}
+}
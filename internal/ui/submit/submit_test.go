@@ -0,0 +1,56 @@
+package submit
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const stackOutput = `kmtnvwpx;kmtnvwpxlzsruqqlnsxpmtywkquqqkuo;jane@example.com;jane/parser;feat: add parser
+zqrsxlyo;zqrsxlyoqmtlvnwkrnzqkqnrxoxkmlzl;jane@example.com;;wip
+rlvkpnrz;rlvkpnrzqnoowoytxnquwvuryrwnrmlp;jane@example.com;jane/ui;feat: use the parser`
+
+const trunkOutput = `main;.;false;false;false;1
+main;origin;true;false;false;1`
+
+func Test_Submit(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Stack("rlvkpnrz")).SetOutput([]byte(stackOutput))
+	commandRunner.Expect(jj.BookmarkList("trunk()")).SetOutput([]byte(trunkOutput))
+	commandRunner.Expect(jj.GetDescription("kmtnvwpx")).SetOutput([]byte("feat: add parser"))
+	commandRunner.Expect(jj.GetDescription("rlvkpnrz")).SetOutput([]byte("feat: use the parser"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.Reviews = context.NewReviews(t.TempDir())
+	assert.NoError(t, ctx.Reviews.Set("rlvkpnrzqnoowoytxnquwvuryrwnrmlp", "7"))
+
+	model := NewModel(ctx, "rlvkpnrz", 100, 20)
+	model.create = "echo creating; echo $base..$bookmark"
+	model.update = "echo $review-updated"
+	tm := teatest.NewTestModel(t, test.NewShell(model))
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("no bookmark"))
+	})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("7-updated"))
+	})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+
+	assert.Equal(t, "main..jane/parser", ctx.Reviews.Get("kmtnvwpxlzsruqqlnsxpmtywkquqqkuo"))
+	assert.Equal(t, "7-updated", ctx.Reviews.Get("rlvkpnrzqnoowoytxnquwvuryrwnrmlp"))
+	assert.Empty(t, ctx.Reviews.Get("zqrsxlyoqmtlvnwkrnzqkqnrxoxkmlzl"))
+}
+
+func Test_Quote(t *testing.T) {
+	assert.Equal(t, `'it'\''s done'`, quote("it's done"))
+}
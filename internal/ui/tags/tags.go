@@ -0,0 +1,250 @@
+package tags
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/common/menu"
+	"github.com/idursun/jjui/internal/ui/context"
+)
+
+type updateItemsMsg struct {
+	items []list.Item
+}
+
+type commandType int
+
+// defines the order of actions in the list
+const (
+	jumpCommand commandType = iota
+	deleteCommand
+)
+
+type item struct {
+	name     string
+	desc     string
+	priority commandType
+	action   tea.Cmd
+}
+
+func (i item) ShortCut() string {
+	return ""
+}
+
+func (i item) FilterValue() string {
+	return i.name
+}
+
+func (i item) Title() string {
+	return i.name
+}
+
+func (i item) Description() string {
+	return i.desc
+}
+
+type styles struct {
+	border lipgloss.Style
+	title  lipgloss.Style
+	text   lipgloss.Style
+}
+
+type Model struct {
+	context    *context.MainContext
+	current    *jj.Commit
+	menu       menu.Menu
+	keymap     config.KeyMappings[key.Binding]
+	input      textinput.Model
+	settingTag bool
+	styles     styles
+}
+
+func (m *Model) Width() int {
+	return m.menu.Width()
+}
+
+func (m *Model) Height() int {
+	return m.menu.Height()
+}
+
+func (m *Model) SetWidth(w int) {
+	m.menu.SetWidth(w)
+}
+
+func (m *Model) SetHeight(h int) {
+	m.menu.SetHeight(h)
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.load
+}
+
+func (m *Model) filtered(filter string) (tea.Model, tea.Cmd) {
+	return m, m.menu.Filtered(filter)
+}
+
+func (m *Model) load() tea.Msg {
+	output, err := m.context.RunCommandImmediate(jj.TagList())
+	if err != nil {
+		return common.FlashMsg{Text: fmt.Sprintf("failed to list tags: %s", strings.TrimSpace(err.Error())), Error: true}
+	}
+	var items []list.Item
+	for _, t := range jj.ParseTagListOutput(string(output)) {
+		target := fmt.Sprintf("%s %s", t.ChangeId, t.Description)
+		if t.Conflict() {
+			target = "conflicted"
+		} else {
+			items = append(items, item{
+				name:     fmt.Sprintf("jump to '%s'", t.Name),
+				desc:     target,
+				priority: jumpCommand,
+				action:   tea.Batch(common.Close, m.jump(t.Name, t.ChangeId)),
+			})
+		}
+		items = append(items, item{
+			name:     fmt.Sprintf("delete '%s'", t.Name),
+			desc:     target,
+			priority: deleteCommand,
+			action:   m.context.RunCommand(jj.TagDelete(t.Name), common.Refresh, common.Close),
+		})
+	}
+	return updateItemsMsg{items: items}
+}
+
+// jump selects the target of the tag, the revisions can only select it when it is in the current revset
+func (m *Model) jump(name string, changeId string) tea.Cmd {
+	return func() tea.Msg {
+		revset := fmt.Sprintf("%s & (%s)", changeId, m.context.CurrentRevset)
+		output, err := m.context.RunCommandImmediate(jj.GetIdsFromRevset(revset))
+		if err == nil && strings.TrimSpace(string(output)) == "" {
+			return common.FlashMsg{Text: fmt.Sprintf("the target of '%s' is not in the current revset", name), Error: true}
+		}
+		return common.RefreshMsg{SelectedRevision: changeId}
+	}
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.settingTag {
+		return m.updateInput(msg)
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.menu.List.SettingFilter() {
+			break
+		}
+		switch {
+		case key.Matches(msg, m.keymap.Cancel):
+			if m.menu.Filter != "" || m.menu.List.IsFiltered() {
+				m.menu.List.ResetFilter()
+				return m.filtered("")
+			}
+			return m, common.Close
+		case key.Matches(msg, m.keymap.Apply):
+			if m.menu.List.SelectedItem() == nil {
+				break
+			}
+			return m, m.menu.List.SelectedItem().(item).action
+		case key.Matches(msg, m.keymap.Tag.Set) && m.current != nil:
+			m.settingTag = true
+			return m, m.input.Focus()
+		case key.Matches(msg, m.keymap.Tag.Revset):
+			return m, tea.Batch(common.Close, common.UpdateRevSet("tags()"))
+		case key.Matches(msg, m.keymap.Tag.Jump) && m.menu.Filter != "jump":
+			return m.filtered("jump")
+		case key.Matches(msg, m.keymap.Tag.Delete) && m.menu.Filter != "delete":
+			return m.filtered("delete")
+		}
+	case updateItemsMsg:
+		m.menu.Items = msg.items
+		slices.SortStableFunc(m.menu.Items, func(a list.Item, b list.Item) int {
+			return int(a.(item).priority) - int(b.(item).priority)
+		})
+		return m, m.menu.List.SetItems(m.menu.Items)
+	}
+	var cmd tea.Cmd
+	m.menu.List, cmd = m.menu.List.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) updateInput(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keymap.Cancel):
+			m.settingTag = false
+			m.input.Blur()
+			m.input.SetValue("")
+			return m, nil
+		case key.Matches(msg, m.keymap.Apply):
+			name := strings.TrimSpace(m.input.Value())
+			if name == "" {
+				return m, nil
+			}
+			return m, m.context.RunCommand(jj.TagSet(m.current.GetChangeId(), name), common.Refresh, common.Close)
+		}
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	m.input.SetValue(strings.ReplaceAll(m.input.Value(), " ", "-"))
+	return m, cmd
+}
+
+func (m *Model) View() string {
+	if m.settingTag {
+		title := m.styles.title.Render(fmt.Sprintf("Set tag on %s", m.current.GetChangeId()))
+		content := lipgloss.JoinVertical(0, title, "", m.input.View())
+		content = lipgloss.Place(m.menu.Width(), lipgloss.Height(content), 0, 0, content)
+		return m.styles.border.Render(m.styles.text.Width(m.menu.Width()).Render(content))
+	}
+	helpKeys := []key.Binding{
+		m.keymap.Tag.Jump,
+		m.keymap.Tag.Delete,
+		m.keymap.Tag.Set,
+		m.keymap.Tag.Revset,
+	}
+	return m.menu.View(helpKeys)
+}
+
+func NewModel(c *context.MainContext, current *jj.Commit, width int, height int) *Model {
+	var items []list.Item
+	keymap := config.Current.GetKeyMap()
+
+	menu := menu.NewMenu(items, width, height, keymap, menu.WithStylePrefix("tags"))
+	menu.Title = "Tags"
+	menu.FilterMatches = func(i list.Item, filter string) bool {
+		return strings.HasPrefix(i.FilterValue(), filter)
+	}
+
+	s := styles{
+		border: common.DefaultPalette.GetBorder("tags menu border", lipgloss.NormalBorder()),
+		title:  common.DefaultPalette.Get("tags menu title").Padding(0, 1, 0, 1),
+		text:   common.DefaultPalette.Get("tags menu text"),
+	}
+
+	input := textinput.New()
+	input.Prompt = " > "
+	input.CharLimit = 120
+	input.PromptStyle = s.title
+	input.TextStyle = s.text
+	input.Cursor.TextStyle = s.text
+
+	m := &Model{
+		context: c,
+		current: current,
+		keymap:  keymap,
+		menu:    menu,
+		input:   input,
+		styles:  s,
+	}
+	m.SetWidth(width)
+	m.SetHeight(height)
+	return m
+}
@@ -0,0 +1,72 @@
+package tags
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var current = &jj.Commit{ChangeId: "kmtnvwpx", CommitId: "0a1b2c3d"}
+
+func Test_SetTag(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.TagList())
+	commandRunner.Expect(jj.TagSet("kmtnvwpx", "v1.0.0"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), current, 0, 0)
+	tm := teatest.NewTestModel(t, test.NewShell(model))
+	tm.Type("s")
+	tm.Type("v1.0.0")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
+func Test_DeleteTag(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.TagList()).SetOutput([]byte("v0.1.0;kmtnvwpx;release\nv0.2.0;zqrsxlyo;release"))
+	commandRunner.Expect(jj.TagDelete("v0.2.0"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), current, 0, 0)
+	tm := teatest.NewTestModel(t, test.NewShell(model))
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("v0.2.0"))
+	})
+	tm.Type("d")
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
+func Test_LoadFailureIsShown(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.TagList()).SetError(errors.New("Error: no such command\n"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), current, 0, 0)
+	assert.Equal(t, common.FlashMsg{Text: "failed to list tags: Error: no such command", Error: true}, model.load())
+}
+
+func Test_JumpOutsideOfRevset(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CurrentRevset = "::@"
+	commandRunner.Expect(jj.GetIdsFromRevset("zqrsxlyo & (::@)"))
+	commandRunner.Expect(jj.GetIdsFromRevset("kmtnvwpx & (::@)")).SetOutput([]byte("kmtn\n"))
+	defer commandRunner.Verify()
+
+	model := NewModel(ctx, current, 0, 0)
+	msg := model.jump("v0.2.0", "zqrsxlyo")()
+	assert.Equal(t, common.FlashMsg{Text: "the target of 'v0.2.0' is not in the current revset", Error: true}, msg)
+	msg = model.jump("v0.1.0", "kmtnvwpx")()
+	assert.Equal(t, common.RefreshMsg{SelectedRevision: "kmtnvwpx"}, msg)
+}
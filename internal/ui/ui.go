@@ -1,7 +1,11 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/idursun/jjui/internal/ui/flash"
@@ -13,20 +17,28 @@ import (
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/screen"
 	"github.com/idursun/jjui/internal/ui/bookmarks"
+	"github.com/idursun/jjui/internal/ui/commandlog"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
 	customcommands "github.com/idursun/jjui/internal/ui/custom_commands"
 	"github.com/idursun/jjui/internal/ui/diff"
+	"github.com/idursun/jjui/internal/ui/divergence"
 	"github.com/idursun/jjui/internal/ui/exec_process"
 	"github.com/idursun/jjui/internal/ui/git"
 	"github.com/idursun/jjui/internal/ui/helppage"
+	"github.com/idursun/jjui/internal/ui/keysequence"
 	"github.com/idursun/jjui/internal/ui/leader"
+	"github.com/idursun/jjui/internal/ui/macro"
+	"github.com/idursun/jjui/internal/ui/metaedit"
 	"github.com/idursun/jjui/internal/ui/oplog"
 	"github.com/idursun/jjui/internal/ui/preview"
 	"github.com/idursun/jjui/internal/ui/revisions"
 	"github.com/idursun/jjui/internal/ui/revset"
 	"github.com/idursun/jjui/internal/ui/status"
+	"github.com/idursun/jjui/internal/ui/submit"
+	"github.com/idursun/jjui/internal/ui/tags"
 	"github.com/idursun/jjui/internal/ui/undo"
+	"github.com/idursun/jjui/internal/ui/watcher"
 )
 
 type Model struct {
@@ -47,6 +59,9 @@ type Model struct {
 	context                 *context.MainContext
 	keyMap                  config.KeyMappings[key.Binding]
 	stacked                 tea.Model
+	watcher                 *watcher.Watcher
+	keySequence             *keysequence.Model
+	macro                   *macro.Model
 }
 
 type triggerAutoRefreshMsg struct{}
@@ -86,6 +101,11 @@ func (m Model) handleFocusInputMessage(msg tea.Msg) (tea.Model, tea.Cmd, bool) {
 		return m, nil, false
 	}
 
+	// commands can be cancelled from any view while they are running
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keyMap.CancelCommand) && m.context.CancelCommands() {
+		return m, nil, true
+	}
+
 	if m.leader != nil {
 		m.leader, cmd = m.leader.Update(msg)
 		return m, cmd, true
@@ -124,6 +144,83 @@ func (m Model) handleFocusInputMessage(msg tea.Msg) (tea.Model, tea.Cmd, bool) {
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case macro.KeyMsg:
+		// the keys are recorded once the key sequences and counts are resolved, they are replayed as they are
+		return m.update(msg)
+	case keysequence.KeyMsg:
+		// the keys resolved from key sequences and counts are not matched against them again
+		return m.update(tea.KeyMsg(msg))
+	case common.ModeActionMsg:
+		model, cmd := m.update(msg.Mode)
+		action := func() tea.Msg { return keysequence.KeyMsg(msg.Action) }
+		return model, tea.Sequence(settled(cmd), action)
+	case tea.KeyMsg:
+		if !m.acceptsKeySequences(msg) {
+			break
+		}
+		if cmd, handled := m.keySequence.Update(msg); handled {
+			return m, cmd
+		}
+	default:
+		if cmd, handled := m.keySequence.Update(msg); handled {
+			return m, cmd
+		}
+	}
+	return m.update(msg)
+}
+
+// acceptsKeySequences reports whether the key can be a part of a key sequence or a count, which
+// are only typed in the revisions and the op log when nothing else captures the keys
+func (m Model) acceptsKeySequences(msg tea.KeyMsg) bool {
+	if m.capturesKeys() || m.macro.Pending() {
+		return false
+	}
+	if m.keySequence.Pending() {
+		return true
+	}
+	for _, command := range m.context.CustomCommands {
+		if key.Matches(msg, command.Binding()) {
+			return false
+		}
+	}
+	return true
+}
+
+// capturesKeys reports whether the keys are typed into a view instead of the revisions and the op log
+func (m Model) capturesKeys() bool {
+	return m.leader != nil || m.diff != nil || m.stacked != nil || m.revsetModel.Editing || m.status.IsFocused() ||
+		m.revisions.IsFocused() || m.revisions.IsAceJumping()
+}
+
+// recordsKey reports whether the key is recorded into a macro, the keys typed into the leader and
+// the command palette are not recorded but the keys they send are
+func (m Model) recordsKey(msg tea.KeyMsg) bool {
+	if m.leader != nil || m.status.InCommandPalette() {
+		return false
+	}
+	return m.capturesKeys() || !key.Matches(msg, m.keyMap.Leader, m.keyMap.CommandPalette)
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch k := msg.(type) {
+	case macro.KeyMsg:
+		msg = tea.KeyMsg(k)
+	case tea.KeyMsg:
+		if m.macro.Pending() || m.macro.Playing() || !m.capturesKeys() {
+			if cmd, handled := m.macro.Update(k); handled {
+				return m, cmd
+			}
+		}
+		if m.recordsKey(k) {
+			m.macro.Record(k)
+		}
+	default:
+		if cmd, handled := m.macro.Update(msg); handled {
+			return m, cmd
+		}
+	}
+
 	if m, cmd, handled := m.handleFocusInputMessage(msg); handled {
 		return m, cmd
 	}
@@ -158,6 +255,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			changeIds := m.revisions.GetCommitIds()
 			m.stacked = bookmarks.NewModel(m.context, m.revisions.SelectedRevision(), changeIds, m.width, m.height)
 			cmds = append(cmds, m.stacked.Init())
+		case key.Matches(msg, m.keyMap.MetaEdit.Mode) && m.revisions.InNormalMode():
+			m.stacked = metaedit.NewModel(m.context, m.revisions.SelectedRevisions())
+			cmds = append(cmds, m.stacked.Init())
+		case key.Matches(msg, m.keyMap.Tag.Mode) && m.revisions.InNormalMode():
+			m.stacked = tags.NewModel(m.context, m.revisions.SelectedRevision(), m.width, m.height)
+			cmds = append(cmds, m.stacked.Init())
+		case key.Matches(msg, m.keyMap.Submit.Mode) && m.revisions.InNormalMode():
+			if selected := m.revisions.SelectedRevision(); selected != nil {
+				m.stacked = submit.NewModel(m.context, selected.GetChangeId(), m.width-2, m.height-2)
+				cmds = append(cmds, m.stacked.Init())
+			}
+		case key.Matches(msg, m.keyMap.Divergence.Mode) && m.revisions.InNormalMode():
+			m.stacked = divergence.NewModel(m.context, m.width-2, m.height-2)
+			cmds = append(cmds, m.stacked.Init())
+		case key.Matches(msg, m.keyMap.CommandLog.Mode) && m.revisions.InNormalMode():
+			m.stacked = commandlog.NewModel(m.context, m.width-2, m.height-2)
+			cmds = append(cmds, m.stacked.Init())
+		case key.Matches(msg, m.keyMap.BookmarkBrowser.Mode) && m.revisions.InNormalMode():
+			m.stacked = bookmarks.NewBrowserModel(m.context, m.width-2, m.height-2)
+			cmds = append(cmds, m.stacked.Init())
 		case key.Matches(msg, m.keyMap.Help):
 			cmds = append(cmds, common.ToggleHelp)
 			return m, tea.Batch(cmds...)
@@ -184,6 +301,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keyMap.CustomCommands):
 			m.stacked = customcommands.NewModel(m.context, m.width, m.height)
 			cmds = append(cmds, m.stacked.Init())
+		case key.Matches(msg, m.keyMap.CommandPalette) && m.revisions.InNormalMode() && m.oplog == nil:
+			return m, common.CommandPalette
 		case key.Matches(msg, m.keyMap.Leader):
 			m.leader = leader.New(m.context)
 			cmds = append(cmds, leader.InitCmd)
@@ -222,6 +341,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case common.ShowDiffMsg:
 		m.diff = diff.New(string(msg), m.width, m.height)
 		return m, m.diff.Init()
+	case common.CommandCompletedMsg:
+		// the cancelled command may have been stopped after changing the repo
+		if errors.Is(msg.Err, common.ErrCommandCancelled) {
+			cmds = append(cmds, common.Refresh)
+		}
 	case common.UpdateRevisionsSuccessMsg:
 		m.state = common.Ready
 	case triggerAutoRefreshMsg:
@@ -311,6 +435,18 @@ func (m Model) View() string {
 		m.status.SetHelp(m.leader)
 	}
 
+	if m.keySequence.Pending() {
+		m.status.SetMode(m.keySequence.String())
+		m.status.SetHelp(m.keySequence)
+	}
+
+	if m.macro.Pending() {
+		m.status.SetMode(m.macro.String())
+		m.status.SetHelp(m.macro)
+	} else if m.macro.Recording() != "" {
+		m.status.SetMode(m.macro.String())
+	}
+
 	footer := m.status.View()
 	footerHeight := lipgloss.Height(footer)
 
@@ -384,15 +520,57 @@ func (m Model) renderLeftView(footerHeight int, topViewHeight int, bottomPreview
 }
 
 func (m Model) scheduleAutoRefresh() tea.Cmd {
+	if m.watcher != nil {
+		return func() tea.Msg {
+			if m.watcher.Wait() {
+				return triggerAutoRefreshMsg{}
+			}
+			return nil
+		}
+	}
 	interval := config.Current.UI.AutoRefreshInterval
 	if interval > 0 {
-		return tea.Tick(time.Duration(interval)*time.Second, func(time.Time) tea.Msg {
+		return common.Tick(time.Duration(interval)*time.Second, func(time.Time) tea.Msg {
 			return triggerAutoRefreshMsg{}
 		})
 	}
 	return nil
 }
 
+// settled runs the command and the commands of the batches it returns, and returns their messages
+// once all of them are done so that the commands sequenced after it see their outcome
+func settled(cmd tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		var cmds tea.BatchMsg
+		for _, msg := range collect(cmd) {
+			cmds = append(cmds, func() tea.Msg { return msg })
+		}
+		return cmds
+	}
+}
+
+func collect(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		return []tea.Msg{msg}
+	}
+	results := make([][]tea.Msg, len(batch))
+	var wg sync.WaitGroup
+	for i, cmd := range batch {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = collect(cmd)
+		}()
+	}
+	wg.Wait()
+	return slices.Concat(results...)
+}
+
 func (m Model) isSafeToQuit() bool {
 	if m.stacked != nil {
 		return false
@@ -422,5 +600,31 @@ func New(c *context.MainContext) tea.Model {
 		status:                  &statusModel,
 		revsetModel:             revset.New(c),
 		flash:                   flash.New(c),
+		watcher:                 newWatcher(c.Location),
+		keySequence:             keysequence.New(),
+		macro:                   macro.New(c, revisionsModel.IsLoading),
+	}
+}
+
+// Close stops watching the repository
+func (m Model) Close() error {
+	if m.watcher == nil {
+		return nil
+	}
+	return m.watcher.Close()
+}
+
+// newWatcher watches the repository when watching is enabled, auto refresh falls back to
+// polling every `auto_refresh_interval` seconds when the repository can't be watched
+func newWatcher(location string) *watcher.Watcher {
+	if !config.Current.Watch.Enabled {
+		return nil
+	}
+	debounce := time.Duration(config.Current.Watch.Debounce) * time.Millisecond
+	w, err := watcher.New(location, config.Current.Watch.Ignore, debounce)
+	if err != nil {
+		log.Println("failed to watch the repository, auto refresh falls back to polling:", err)
+		return nil
 	}
+	return w
 }
@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRevset = "::@"
+
+func newTestContext(commandRunner context.CommandRunner) *context.MainContext {
+	ctx := test.NewTestContext(commandRunner)
+	ctx.JJConfig = &config.JJConfig{}
+	ctx.Histories = config.NewHistories()
+	ctx.DefaultRevset = testRevset
+	ctx.CurrentRevset = testRevset
+	return ctx
+}
+
+func logOutput() []byte {
+	var lb test.LogBuilder
+	lb.Write("@  id=kkmpptxz author=some@author id=8b1e95e3")
+	lb.Write("│  working copy")
+	lb.Write("○  id=nyqzpsmt author=some@author id=5233c94f")
+	lb.Write("│  add the feature")
+	lb.Write("◆  id=zzzzzzzz root() id=00000000")
+	return []byte(lb.String())
+}
+
+func TestUI_Initial(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Log(testRevset, config.Current.Limit)).SetOutput(logOutput())
+	defer commandRunner.Verify()
+
+	h := test.NewHarness(t, New(newTestContext(commandRunner)))
+	h.RequireGolden("initial")
+}
+
+func TestUI_HelpOverlay(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Log(testRevset, config.Current.Limit)).SetOutput(logOutput())
+	defer commandRunner.Verify()
+
+	h := test.NewHarness(t, New(newTestContext(commandRunner)))
+	h.Resize(140, 60)
+	h.Press("?")
+	h.RequireGolden("help")
+	h.Press("esc")
+	h.RequireGolden("closed")
+}
+
+func TestUI_EditRevset(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Log(testRevset, config.Current.Limit)).SetOutput(logOutput())
+	defer commandRunner.Verify()
+
+	h := test.NewHarness(t, New(newTestContext(commandRunner)))
+	h.Press("L")
+	h.Type("main")
+	h.RequireGolden("editing")
+}
+
+func TestUI_Resize(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Log(testRevset, config.Current.Limit)).SetOutput(logOutput())
+	defer commandRunner.Verify()
+
+	h := test.NewHarness(t, New(newTestContext(commandRunner)))
+	h.Resize(40, 8)
+	h.RequireGolden("small")
+}
+
+func TestUI_Count(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Log(testRevset, config.Current.Limit)).SetOutput(logOutput())
+	defer commandRunner.Verify()
+
+	h := test.NewHarness(t, New(newTestContext(commandRunner)))
+	h.Type("2")
+	h.RequireGolden("pending")
+	h.Type("j")
+	h.RequireGolden("moved")
+}
+
+func TestUI_BookmarkBrowser(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Log(testRevset, config.Current.Limit)).SetOutput(logOutput())
+	commandRunner.Expect(jj.BookmarkListRefs()).SetOutput([]byte("feature;.;false;false;true;nyqzpsmt;0;0;1700000000;2 days ago\n"))
+	defer commandRunner.Verify()
+
+	h := test.NewHarness(t, New(newTestContext(commandRunner)))
+	h.Press("ctrl+b")
+	h.RequireGolden("open")
+}
+
+func TestUI_Macro(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Log(testRevset, config.Current.Limit)).SetOutput(logOutput())
+	defer commandRunner.Verify()
+
+	ctx := newTestContext(commandRunner)
+	h := test.NewHarness(t, New(ctx))
+	h.Type("Qa")
+	h.RequireGolden("recording")
+	h.Type("jQ")
+	h.Type(".a")
+	h.RequireGolden("replayed")
+	assert.Equal(t, "zzzzzzzz", ctx.SelectedItem.(context.SelectedRevision).ChangeId)
+}
+
+func TestUI_CommandPalette(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Log(testRevset, config.Current.Limit)).SetOutput(logOutput())
+	defer commandRunner.Verify()
+
+	h := test.NewHarness(t, New(newTestContext(commandRunner)))
+	h.Resize(100, 40)
+	h.Press("ctrl+k")
+	h.RequireGolden("open")
+	h.Type("abandon")
+	h.RequireGolden("filtered")
+	h.Press("enter")
+	h.RequireGolden("run")
+}
+
+func TestNewWatcher_WithoutAutoRefreshInterval(t *testing.T) {
+	location := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(location, ".jj", "repo", "op_heads", "heads"), 0o755))
+
+	original := config.Current.UI.AutoRefreshInterval
+	config.Current.UI.AutoRefreshInterval = 0
+	defer func() { config.Current.UI.AutoRefreshInterval = original }()
+
+	w := newWatcher(location)
+	require.NotNil(t, w)
+	require.NoError(t, Model{watcher: w}.Close())
+	assert.False(t, w.Wait())
+}
+
+func TestUI_ModeActionWaitsForTheMode(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Log(testRevset, config.Current.Limit)).SetOutput(logOutput())
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status("kkmpptxz")).SetOutput([]byte("false false\nM file.txt\n"))
+	commandRunner.Expect(jj.Diff("kkmpptxz", "file.txt")).SetOutput([]byte("the diff of file.txt"))
+	defer commandRunner.Verify()
+
+	tm := teatest.NewTestModel(t, New(newTestContext(commandRunner)), teatest.WithInitialTermSize(100, 40))
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("working copy"))
+	})
+	// the diff is shown for the file listed once the details are loaded
+	tm.Send(common.ModeActionMsg{Mode: test.Key("l"), Action: test.Key("d")})
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("the diff of file.txt"))
+	})
+	tm.Send(tea.QuitMsg{})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second))
+}
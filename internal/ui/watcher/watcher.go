@@ -0,0 +1,195 @@
+package watcher
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// alwaysIgnored are the directories of the working copy which are never watched, changes in
+// the repository are picked up from the operation heads instead
+var alwaysIgnored = []string{".jj", ".git"}
+
+// Watcher reports the changes of the operation heads of the repository and of the files in the
+// working copy, waiting for the changes to settle before reporting them
+type Watcher struct {
+	watcher  *fsnotify.Watcher
+	root     string
+	opHeads  string
+	ignore   []string
+	debounce time.Duration
+	changes  chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// New starts watching the repository at the location, it returns an error when the repository
+// can't be watched, e.g. when the limit of the watched directories is reached
+func New(location string, ignore []string, debounce time.Duration) (*Watcher, error) {
+	opHeads, err := opHeadsDir(location)
+	if err != nil {
+		return nil, err
+	}
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{
+		watcher:  fw,
+		root:     location,
+		opHeads:  opHeads,
+		ignore:   slices.Concat(alwaysIgnored, ignore),
+		debounce: debounce,
+		changes:  make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	if err := fw.Add(opHeads); err != nil {
+		fw.Close()
+		return nil, err
+	}
+	if err := w.addTree(location); err != nil {
+		fw.Close()
+		return nil, err
+	}
+	go w.run()
+	return w, nil
+}
+
+// opHeadsDir returns the directory of the operation heads, the repository of a secondary
+// workspace is stored elsewhere and `.jj/repo` is a file with its path
+func opHeadsDir(location string) (string, error) {
+	repo := filepath.Join(location, ".jj", "repo")
+	info, err := os.Stat(repo)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		content, err := os.ReadFile(repo)
+		if err != nil {
+			return "", err
+		}
+		repo = strings.TrimSpace(string(content))
+		if !filepath.IsAbs(repo) {
+			repo = filepath.Join(location, ".jj", repo)
+		}
+	}
+	opHeads := filepath.Join(repo, "op_heads", "heads")
+	if _, err := os.Stat(opHeads); err != nil {
+		return "", err
+	}
+	return opHeads, nil
+}
+
+// addTree watches the directory and all the directories under it which are not ignored
+func (w *Watcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// the directory can be removed while it is being walked
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != w.root && w.ignored(path) {
+			return filepath.SkipDir
+		}
+		return w.watcher.Add(path)
+	})
+}
+
+// ignored reports whether the path of the working copy, or any of its parent directories,
+// matches one of the ignore patterns
+func (w *Watcher) ignored(path string) bool {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil || rel == "." {
+		return false
+	}
+	parts := strings.Split(rel, string(filepath.Separator))
+	for _, pattern := range w.ignore {
+		if matched, _ := filepath.Match(pattern, rel); matched {
+			return true
+		}
+		for _, part := range parts {
+			if matched, _ := filepath.Match(pattern, part); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (w *Watcher) relevant(event fsnotify.Event) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+	if filepath.Dir(event.Name) == w.opHeads {
+		return true
+	}
+	return !w.ignored(event.Name)
+}
+
+func (w *Watcher) run() {
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if !w.relevant(event) {
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := w.addTree(event.Name); err != nil {
+						log.Println("failed to watch", event.Name, err)
+					}
+				}
+			}
+			timer.Reset(w.debounce)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			// events can be lost when the queue overflows, a refresh makes sure nothing is missed
+			log.Println("watcher error", err)
+			timer.Reset(w.debounce)
+		case <-timer.C:
+			select {
+			case w.changes <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Wait blocks until there are changes, it returns false when the watcher is closed
+func (w *Watcher) Wait() bool {
+	select {
+	case <-w.changes:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+func (w *Watcher) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.done)
+		err = w.watcher.Close()
+	})
+	return err
+}
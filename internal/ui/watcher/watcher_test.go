@@ -0,0 +1,102 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const debounce = 20 * time.Millisecond
+
+// newRepo creates the directories of a repository which are watched
+func newRepo(t *testing.T) string {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".jj", "repo", "op_heads", "heads"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "src"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "target"), 0o755))
+	return root
+}
+
+func changed(w *Watcher) bool {
+	result := make(chan bool, 1)
+	go func() {
+		result <- w.Wait()
+	}()
+	select {
+	case r := <-result:
+		return r
+	case <-time.After(10 * debounce):
+		return false
+	}
+}
+
+func write(t *testing.T, path string) {
+	require.NoError(t, os.WriteFile(path, []byte("content"), 0o644))
+}
+
+func TestWatcher_WorkingCopyChanges(t *testing.T) {
+	root := newRepo(t)
+	w, err := New(root, []string{"target"}, debounce)
+	require.NoError(t, err)
+	defer w.Close()
+
+	write(t, filepath.Join(root, "src", "main.go"))
+	write(t, filepath.Join(root, "src", "util.go"))
+	assert.True(t, changed(w))
+	// the changes are reported once they settle
+	assert.False(t, changed(w))
+
+	write(t, filepath.Join(root, "target", "output"))
+	write(t, filepath.Join(root, ".jj", "working_copy"))
+	assert.False(t, changed(w))
+}
+
+func TestWatcher_NewDirectoriesAreWatched(t *testing.T) {
+	root := newRepo(t)
+	w, err := New(root, nil, debounce)
+	require.NoError(t, err)
+	defer w.Close()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "docs"), 0o755))
+	assert.True(t, changed(w))
+
+	write(t, filepath.Join(root, "docs", "README.md"))
+	assert.True(t, changed(w))
+}
+
+func TestWatcher_OperationChanges(t *testing.T) {
+	root := newRepo(t)
+	w, err := New(root, nil, debounce)
+	require.NoError(t, err)
+	defer w.Close()
+
+	write(t, filepath.Join(root, ".jj", "repo", "op_heads", "heads", "abc"))
+	assert.True(t, changed(w))
+}
+
+func TestWatcher_SecondaryWorkspace(t *testing.T) {
+	root := newRepo(t)
+	workspace := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(workspace, ".jj"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, ".jj", "repo"), []byte(filepath.Join(root, ".jj", "repo")), 0o644))
+
+	opHeads, err := opHeadsDir(workspace)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, ".jj", "repo", "op_heads", "heads"), opHeads)
+}
+
+func TestNew_FailsOutsideRepository(t *testing.T) {
+	_, err := New(t.TempDir(), nil, debounce)
+	assert.Error(t, err)
+}
+
+func TestWatcher_Close(t *testing.T) {
+	w, err := New(newRepo(t), nil, debounce)
+	require.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.False(t, w.Wait())
+}
@@ -23,6 +23,12 @@
 
         ldflags = [ "-X main.Version=${version}" ];
         vendorHash = builtins.readFile ./vendor-hash;
+        # the repository tests in ./test run jj, they fail instead of being skipped without it
+        nativeCheckInputs = [ pkgs.jujutsu ];
+        preCheck = ''
+          export HOME=$TMPDIR
+          export JJUI_REQUIRE_JJ=1
+        '';
         meta.mainProgram = "jjui";
       };
 
@@ -36,6 +42,7 @@
         nativeBuildInputs = [
           pkgs.go
           pkgs.gopls
+          pkgs.jujutsu
         ];
       };
     };
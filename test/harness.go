@@ -0,0 +1,267 @@
+package test
+
+import (
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/idursun/jjui/internal/ui/common"
+)
+
+// maxMessages stops the harness when the model keeps producing messages
+const maxMessages = 1000
+
+var keyTypes = map[string]tea.KeyType{
+	"enter":     tea.KeyEnter,
+	"esc":       tea.KeyEscape,
+	"tab":       tea.KeyTab,
+	"shift+tab": tea.KeyShiftTab,
+	"backspace": tea.KeyBackspace,
+	"delete":    tea.KeyDelete,
+	"space":     tea.KeySpace,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"home":      tea.KeyHome,
+	"end":       tea.KeyEnd,
+	"pgup":      tea.KeyPgUp,
+	"pgdown":    tea.KeyPgDown,
+}
+
+// libraryTimers are the functions of the libraries which create the commands of timers, like the
+// blinking of the cursor and the frames of the spinner, they are only cosmetic and never run
+var libraryTimers = []string{
+	"github.com/charmbracelet/bubbletea.Tick.func1",
+	"github.com/charmbracelet/bubbletea.Every.func1",
+	"github.com/charmbracelet/bubbles/cursor.(*Model).BlinkCmd.func1",
+}
+
+// timerMsg is a timer created with common.Tick, it is run when the clock of the harness is advanced
+type timerMsg struct {
+	duration time.Duration
+	fn       func(time.Time) tea.Msg
+}
+
+type timer struct {
+	at time.Time
+	fn func(time.Time) tea.Msg
+}
+
+// Harness drives a model synchronously, the messages produced by its commands are fed back
+// to the model before the next key is sent, so that its view can be compared to golden files.
+// The timers run on the clock of the harness, which only moves when it is advanced.
+type Harness struct {
+	t        *testing.T
+	model    tea.Model
+	width    int
+	height   int
+	now      time.Time
+	timers   []timer
+	messages int
+}
+
+// NewHarness initialises the model and sizes it to 100x30
+func NewHarness(t *testing.T, model tea.Model) *Harness {
+	h := &Harness{t: t, model: model, now: time.Unix(0, 0)}
+	tick := common.Tick
+	common.Tick = func(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd {
+		return func() tea.Msg {
+			return timerMsg{duration: d, fn: fn}
+		}
+	}
+	t.Cleanup(func() { common.Tick = tick })
+	h.process(model.Init())
+	h.Resize(100, 30)
+	return h
+}
+
+func (h *Harness) Model() tea.Model {
+	return h.model
+}
+
+// Resize sends a window size message
+func (h *Harness) Resize(width int, height int) {
+	h.width = width
+	h.height = height
+	h.Send(tea.WindowSizeMsg{Width: width, Height: height})
+}
+
+// Send updates the model with the message and the messages produced by its commands
+func (h *Harness) Send(msg tea.Msg) {
+	h.t.Helper()
+	var cmd tea.Cmd
+	h.model, cmd = h.model.Update(msg)
+	h.process(cmd)
+}
+
+// Press sends the keys one by one, a key is either a single character or a name such as
+// "enter", "esc", "ctrl+r" or "alt+j"
+func (h *Harness) Press(keys ...string) {
+	h.t.Helper()
+	for _, k := range keys {
+		h.Send(Key(k))
+	}
+}
+
+// Advance moves the clock of the harness, running the timers which are due in order
+func (h *Harness) Advance(d time.Duration) {
+	h.t.Helper()
+	until := h.now.Add(d)
+	for {
+		i := slices.IndexFunc(h.timers, func(t timer) bool { return !t.at.After(until) })
+		if i == -1 {
+			break
+		}
+		for j, t := range h.timers {
+			if t.at.Before(h.timers[i].at) {
+				i = j
+			}
+		}
+		t := h.timers[i]
+		h.timers = slices.Delete(h.timers, i, i+1)
+		h.now = t.at
+		h.handle(t.fn(h.now))
+	}
+	h.now = until
+}
+
+// Type sends each character of the text as a key
+func (h *Harness) Type(text string) {
+	h.t.Helper()
+	for _, r := range text {
+		h.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+// View returns the view of the model as it is shown on the screen, i.e. cut to the size of the
+// window like the renderer of bubbletea does, without the escape sequences and the trailing spaces
+func (h *Harness) View() string {
+	lines := strings.Split(h.model.View(), "\n")
+	if len(lines) > h.height {
+		lines = lines[len(lines)-h.height:]
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(ansi.Strip(ansi.Truncate(line, h.width, "")), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RequireGolden compares the view with testdata/<test name>/<frame>.golden, the golden files
+// are written when the tests are run with -update
+func (h *Harness) RequireGolden(frame string) {
+	h.t.Helper()
+	golden.RequireEqual(frameTB{TB: h.t, name: h.t.Name() + "/" + frame}, []byte(h.View()+"\n"))
+}
+
+// frameTB names the golden file after the frame as well as the test
+type frameTB struct {
+	testing.TB
+	name string
+}
+
+func (f frameTB) Name() string {
+	return f.name
+}
+
+// process runs the command and feeds its messages to the model until there are no more, the
+// commands of a batch run concurrently while the ones of a sequence run one after another
+func (h *Harness) process(cmd tea.Cmd) {
+	if cmd == nil || isLibraryTimer(cmd) {
+		return
+	}
+	h.handle(cmd())
+}
+
+func (h *Harness) handle(msg tea.Msg) {
+	if msg == nil {
+		return
+	}
+	if cmds, ok := sequence(msg); ok {
+		for _, cmd := range cmds {
+			h.process(cmd)
+		}
+		return
+	}
+	if cmds, ok := msg.(tea.BatchMsg); ok {
+		for _, msg := range run(cmds) {
+			h.handle(msg)
+		}
+		return
+	}
+	switch msg := msg.(type) {
+	case timerMsg:
+		h.timers = append(h.timers, timer{at: h.now.Add(msg.duration), fn: msg.fn})
+		return
+	case tea.QuitMsg:
+		return
+	}
+	if h.messages++; h.messages > maxMessages {
+		h.t.Fatalf("model produced more than %d messages", maxMessages)
+	}
+	var next tea.Cmd
+	h.model, next = h.model.Update(msg)
+	h.process(next)
+}
+
+// run runs the commands concurrently and returns their messages in the order of the commands
+func run(cmds []tea.Cmd) []tea.Msg {
+	messages := make([]tea.Msg, len(cmds))
+	var wg sync.WaitGroup
+	for i, cmd := range cmds {
+		if cmd == nil || isLibraryTimer(cmd) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			messages[i] = cmd()
+		}()
+	}
+	wg.Wait()
+	return messages
+}
+
+// sequence returns the commands of a sequence, which is a slice of commands of an unexported type
+func sequence(msg tea.Msg) ([]tea.Cmd, bool) {
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Slice || v.Type().Elem() != reflect.TypeOf(tea.Cmd(nil)) || v.Type() == reflect.TypeOf(tea.BatchMsg(nil)) {
+		return nil, false
+	}
+	cmds := make([]tea.Cmd, v.Len())
+	for i := range cmds {
+		cmds[i] = v.Index(i).Interface().(tea.Cmd)
+	}
+	return cmds, true
+}
+
+func isLibraryTimer(cmd tea.Cmd) bool {
+	return slices.Contains(libraryTimers, runtime.FuncForPC(reflect.ValueOf(cmd).Pointer()).Name())
+}
+
+// Key returns the key message of a single character or a key name such as "enter" or "ctrl+r"
+func Key(k string) tea.KeyMsg {
+	if keyType, ok := keyTypes[k]; ok {
+		return tea.KeyMsg{Type: keyType}
+	}
+	if letter, ok := strings.CutPrefix(k, "ctrl+"); ok && len(letter) == 1 && letter[0] >= 'a' && letter[0] <= 'z' {
+		return tea.KeyMsg{Type: tea.KeyCtrlA + tea.KeyType(letter[0]-'a')}
+	}
+	alt := false
+	if rest, ok := strings.CutPrefix(k, "alt+"); ok && rest != "" {
+		alt = true
+		k = rest
+	}
+	if utf8.RuneCountInString(k) != 1 {
+		panic("unknown key " + k)
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k), Alt: alt}
+}
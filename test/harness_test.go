@@ -0,0 +1,91 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder records the messages it receives, the commands to return are given per message
+type recorder struct {
+	received []string
+	cmds     map[string]tea.Cmd
+}
+
+func (r *recorder) Init() tea.Cmd {
+	return nil
+}
+
+func (r *recorder) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if s, ok := msg.(string); ok {
+		r.received = append(r.received, s)
+		return r, r.cmds[s]
+	}
+	return r, nil
+}
+
+func (r *recorder) View() string {
+	return ""
+}
+
+func send(s string) tea.Cmd {
+	return func() tea.Msg {
+		return s
+	}
+}
+
+func slow(s string) tea.Cmd {
+	return func() tea.Msg {
+		time.Sleep(100 * time.Millisecond)
+		return s
+	}
+}
+
+func TestHarness_WaitsForSlowCommands(t *testing.T) {
+	r := &recorder{cmds: map[string]tea.Cmd{"start": tea.Batch(slow("slow"), send("fast"))}}
+	h := NewHarness(t, r)
+	h.Send("start")
+	assert.Equal(t, []string{"start", "slow", "fast"}, r.received)
+}
+
+func TestHarness_RunsSequencesInOrder(t *testing.T) {
+	r := &recorder{cmds: map[string]tea.Cmd{
+		"start": tea.Sequence(slow("first"), send("second")),
+		"first": send("after first"),
+	}}
+	h := NewHarness(t, r)
+	h.Send("start")
+	assert.Equal(t, []string{"start", "first", "after first", "second"}, r.received)
+}
+
+func TestHarness_RunsTimersWhenAdvanced(t *testing.T) {
+	r := &recorder{}
+	h := NewHarness(t, r)
+	// the timers are created once the harness replaces the clock
+	r.cmds = map[string]tea.Cmd{
+		"start": tea.Batch(
+			common.Tick(2*time.Second, func(time.Time) tea.Msg { return "late" }),
+			common.Tick(time.Second, func(time.Time) tea.Msg { return "early" }),
+		),
+		"early": common.Tick(500*time.Millisecond, func(time.Time) tea.Msg { return "after early" }),
+	}
+	h.Send("start")
+	assert.Equal(t, []string{"start"}, r.received)
+
+	h.Advance(time.Second)
+	assert.Equal(t, []string{"start", "early"}, r.received)
+	h.Advance(time.Second)
+	assert.Equal(t, []string{"start", "early", "after early", "late"}, r.received)
+}
+
+func TestHarness_SkipsLibraryTimers(t *testing.T) {
+	r := &recorder{cmds: map[string]tea.Cmd{
+		"start": tea.Batch(tea.Tick(time.Hour, func(time.Time) tea.Msg { return "never" }), send("done")),
+	}}
+	h := NewHarness(t, r)
+	h.Send("start")
+	assert.Equal(t, []string{"start", "done"}, r.received)
+}
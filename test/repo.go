@@ -0,0 +1,181 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	appContext "github.com/idursun/jjui/internal/ui/context"
+)
+
+// Commit declares a commit of a repository built by NewRepo
+type Commit struct {
+	// Name is the description of the commit, and how it is referred to in the graph and the tests
+	Name string
+	// Parents are the names of the parent commits, the commit is created on the root when empty.
+	// Commits with more than one parent are merges, they are conflicted when their parents change
+	// the same file differently
+	Parents []string
+	// Files are written to the commit with the given content
+	Files map[string]string
+	// Bookmarks are created pointing to the commit
+	Bookmarks []string
+	// DivergeAs makes the commit divergent by describing it concurrently with the given description
+	DivergeAs string
+}
+
+// Repo is a temporary jj repository. The jj commands run in the test, including the ones run by
+// MainCommandRunner, use the fixed author and ignore the configuration of the user
+type Repo struct {
+	t         *testing.T
+	Dir       string
+	changeIds map[string]string
+}
+
+// RequireJJ skips the test when jj is not installed, it fails the test instead when JJUI_REQUIRE_JJ
+// is set so that the tests are not skipped where jj is expected, like in the nix flake check
+func RequireJJ(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("jj"); err != nil {
+		if os.Getenv("JJUI_REQUIRE_JJ") != "" {
+			t.Fatal("jj is not installed but JJUI_REQUIRE_JJ is set")
+		}
+		t.Skip("jj is not installed")
+	}
+}
+
+// NewRepo creates a repository with the commits in the given order, the parents of a commit must
+// come before it. The working copy is a new empty commit on top of the last one.
+func NewRepo(t *testing.T, commits ...Commit) *Repo {
+	t.Helper()
+	RequireJJ(t)
+	dir := t.TempDir()
+	// descriptions are combined without an editor, e.g. when squashing
+	configFile := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(configFile, []byte("[ui]\neditor = \"true\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("JJ_CONFIG", configFile)
+	t.Setenv("JJ_USER", "Test User")
+	t.Setenv("JJ_EMAIL", "test.user@example.com")
+
+	r := &Repo{t: t, Dir: dir, changeIds: make(map[string]string)}
+	r.JJ("git", "init")
+	for _, commit := range commits {
+		r.create(commit)
+	}
+	for _, commit := range commits {
+		if commit.DivergeAs != "" {
+			r.diverge(commit)
+		}
+	}
+	if len(commits) > 0 {
+		// the last commit can be divergent
+		r.JJ("new", fmt.Sprintf("latest(%s)", r.ChangeId(commits[len(commits)-1].Name)))
+	}
+	return r
+}
+
+func (r *Repo) create(commit Commit) {
+	r.t.Helper()
+	if _, exists := r.changeIds[commit.Name]; exists {
+		r.t.Fatalf("commit %q is declared twice", commit.Name)
+	}
+	args := []string{"new", "--message", commit.Name}
+	if len(commit.Parents) == 0 {
+		args = append(args, "root()")
+	}
+	for _, parent := range commit.Parents {
+		args = append(args, r.ChangeId(parent))
+	}
+	r.JJ(args...)
+	r.changeIds[commit.Name] = r.Log("@", "change_id")
+	for name, content := range commit.Files {
+		path := filepath.Join(r.Dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			r.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			r.t.Fatal(err)
+		}
+	}
+	for _, bookmark := range commit.Bookmarks {
+		r.JJ("bookmark", "create", bookmark, "--revision", "@")
+	}
+}
+
+// diverge rewrites the commit twice at the same operation, the concurrent operations are merged
+// by the next command leaving two commits with the same change id: one with the new description
+// and one with the original description and a reset author
+func (r *Repo) diverge(commit Commit) {
+	r.t.Helper()
+	changeId := r.ChangeId(commit.Name)
+	operation := r.JJ("op", "log", "--no-graph", "--limit", "1", "--template", "id")
+	r.JJ("describe", "--at-operation", operation, "--message", commit.DivergeAs, changeId)
+	r.JJ("describe", "--at-operation", operation, "--reset-author", "--no-edit", changeId)
+	r.JJ("log", "--revisions", "none()")
+}
+
+// JJ runs jj in the repository and returns its trimmed output, it fails the test when jj fails
+func (r *Repo) JJ(args ...string) string {
+	r.t.Helper()
+	c := exec.Command("jj", append([]string{"--color", "never"}, args...)...)
+	c.Dir = r.Dir
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	output, err := c.Output()
+	if err != nil {
+		r.t.Fatalf("jj %s failed: %v\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// Log returns the template evaluated for each revision of the revset, one per line
+func (r *Repo) Log(revset string, template string) string {
+	r.t.Helper()
+	return r.JJ("log", "--no-graph", "--revisions", revset, "--template", template+` ++ "\n"`)
+}
+
+// ChangeId returns the change id of the named commit, it resolves to both commits when the commit
+// is divergent
+func (r *Repo) ChangeId(name string) string {
+	r.t.Helper()
+	changeId, ok := r.changeIds[name]
+	if !ok {
+		r.t.Fatalf("unknown commit %q", name)
+	}
+	return changeId
+}
+
+// Describe returns the first line of the description of the revisions of the revset, one per line
+func (r *Repo) Describe(revset string) string {
+	r.t.Helper()
+	return r.Log(revset, "description.first_line()")
+}
+
+// Parents returns the descriptions of the parents of the named commit, one per line
+func (r *Repo) Parents(name string) string {
+	r.t.Helper()
+	return r.Describe(fmt.Sprintf("parents(%s)", r.ChangeId(name)))
+}
+
+// Context returns the context of the UI running the commands in the repository, the interactive
+// commands run without the terminal
+func (r *Repo) Context() *appContext.MainContext {
+	ctx := appContext.NewAppContext(r.Dir)
+	ctx.CommandRunner = nonInteractiveRunner{ctx.CommandRunner}
+	return ctx
+}
+
+type nonInteractiveRunner struct {
+	appContext.CommandRunner
+}
+
+func (r nonInteractiveRunner) RunInteractiveCommand(args []string, continuation tea.Cmd) tea.Cmd {
+	return r.RunCommand(args, continuation)
+}
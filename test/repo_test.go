@@ -0,0 +1,82 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/operations/abandon"
+	"github.com/idursun/jjui/internal/ui/operations/rebase"
+	"github.com/idursun/jjui/internal/ui/operations/squash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepo_Graph(t *testing.T) {
+	repo := NewRepo(t,
+		Commit{Name: "base", Files: map[string]string{"file.txt": "base\n"}, Bookmarks: []string{"main"}},
+		Commit{Name: "left", Parents: []string{"base"}, Files: map[string]string{"file.txt": "left\n"}},
+		Commit{Name: "right", Parents: []string{"base"}, Files: map[string]string{"file.txt": "right\n"}},
+		Commit{Name: "merge", Parents: []string{"left", "right"}},
+	)
+
+	assert.ElementsMatch(t, []string{"left", "right"}, strings.Split(repo.Parents("merge"), "\n"))
+	assert.Equal(t, "base", repo.Describe("main"))
+	assert.Equal(t, "merge", repo.Describe("conflicts() ~ @"))
+	assert.Equal(t, "merge", repo.Describe("@-"))
+}
+
+func TestRepo_Divergent(t *testing.T) {
+	repo := NewRepo(t,
+		Commit{Name: "feature", DivergeAs: "other feature"},
+	)
+
+	assert.ElementsMatch(t, []string{"feature", "other feature"}, strings.Split(repo.Describe("divergent()"), "\n"))
+}
+
+func TestRepo_Rebase(t *testing.T) {
+	repo := NewRepo(t,
+		Commit{Name: "base"},
+		Commit{Name: "first", Parents: []string{"base"}},
+		Commit{Name: "second", Parents: []string{"base"}},
+	)
+	ctx := repo.Context()
+
+	from := jj.NewSelectedRevisions(&jj.Commit{ChangeId: repo.ChangeId("second")})
+	op := rebase.NewOperation(ctx, from, rebase.SourceRevision, rebase.TargetDestination)
+	h := NewHarness(t, NewOperationHost(op, &jj.Commit{ChangeId: repo.ChangeId("first")}))
+	h.Press("enter")
+	assert.Equal(t, "first", repo.Parents("second"))
+}
+
+func TestRepo_Squash(t *testing.T) {
+	repo := NewRepo(t,
+		Commit{Name: "base", Files: map[string]string{"base.txt": "base\n"}},
+		Commit{Name: "fixup", Parents: []string{"base"}, Files: map[string]string{"fixup.txt": "fixup\n"}},
+		Commit{Name: "child", Parents: []string{"fixup"}},
+	)
+	ctx := repo.Context()
+
+	from := jj.NewSelectedRevisions(&jj.Commit{ChangeId: repo.ChangeId("fixup")})
+	op := squash.NewOperation(ctx, from)
+	h := NewHarness(t, NewOperationHost(op, &jj.Commit{ChangeId: repo.ChangeId("base")}))
+	h.Press("enter")
+	assert.Equal(t, "base", repo.Parents("child"))
+	assert.Equal(t, "base.txt\nfixup.txt", repo.JJ("file", "list", "--revision", repo.ChangeId("base")))
+}
+
+func TestRepo_Abandon(t *testing.T) {
+	repo := NewRepo(t,
+		Commit{Name: "base"},
+		Commit{Name: "abandoned", Parents: []string{"base"}, Bookmarks: []string{"feature"}},
+		Commit{Name: "child", Parents: []string{"abandoned"}},
+	)
+	ctx := repo.Context()
+
+	abandoned := &jj.Commit{ChangeId: repo.ChangeId("abandoned")}
+	op := abandon.NewOperation(ctx, jj.NewSelectedRevisions(abandoned))
+	h := NewHarness(t, NewOperationHost(op, abandoned))
+	h.Press("y")
+	assert.Equal(t, "base", repo.Parents("child"))
+	// the bookmarks of the abandoned revision are kept on its parent
+	assert.Equal(t, "base", repo.Describe("feature"))
+}
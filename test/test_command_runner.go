@@ -17,6 +17,7 @@ import (
 type ExpectedCommand struct {
 	args   []string
 	output []byte
+	err    error
 	called bool
 }
 
@@ -25,6 +26,11 @@ func (e *ExpectedCommand) SetOutput(output []byte) *ExpectedCommand {
 	return e
 }
 
+func (e *ExpectedCommand) SetError(err error) *ExpectedCommand {
+	e.err = err
+	return e
+}
+
 type CommandRunner struct {
 	*testing.T
 	expectations map[string][]*ExpectedCommand
@@ -44,7 +50,7 @@ func (t *CommandRunner) RunCommandImmediate(args []string) ([]byte, error) {
 	for _, e := range expectations {
 		if slices.Equal(e.args, args) {
 			e.called = true
-			return e.output, nil
+			return e.output, e.err
 		}
 	}
 	assert.Fail(t, "unexpected command", subCommand)
@@ -55,7 +61,7 @@ func (t *CommandRunner) RunCommandStreaming(_ context.Context, args []string) (*
 	reader, err := t.RunCommandImmediate(args)
 	return &appContext.StreamingCommand{
 		ReadCloser: io.NopCloser(bytes.NewReader(reader)),
-		ErrPipe:    nil,
+		ErrPipe:    io.NopCloser(bytes.NewReader(nil)),
 	}, err
 }
 
@@ -73,6 +79,10 @@ func (t *CommandRunner) RunInteractiveCommand(args []string, continuation tea.Cm
 	return t.RunCommand(args, continuation)
 }
 
+func (t *CommandRunner) CancelCommands() bool {
+	return false
+}
+
 func (t *CommandRunner) Expect(args []string) *ExpectedCommand {
 	subCommand := args[0]
 	if _, ok := t.expectations[subCommand]; !ok {